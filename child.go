@@ -0,0 +1,259 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// ChildOptions are the options for Window.BeginChildAdv().
+type ChildOptions struct {
+	// Border draws a border around the child region with
+	// Style.ChildBorderColor and Style.ChildBorderWidth.
+	Border bool
+
+	// ShowScrollBar indicates if a scroll bar should be attached to the right
+	// side of the child region.
+	ShowScrollBar bool
+
+	// ShowHorizontalScrollBar indicates if a scroll bar for scrolling the
+	// widgets sideways should be attached to the bottom of the child region.
+	ShowHorizontalScrollBar bool
+}
+
+// childState is the state of a child region between BeginChild() and
+// EndChild() or of the open cell of a table.
+type childState struct {
+	// id is the id of the child region, which is also the key its scroll
+	// position is stored under.
+	id string
+
+	// options are the options the child region was started with.
+	options ChildOptions
+
+	// isCell is true if the region is a cell of a table instead of a child
+	// region started with BeginChild().
+	isCell bool
+
+	// rect is the whole child region including the border and the scroll
+	// bars as [x,y,w,h] in display coordinates.
+	rect mgl.Vec4
+
+	// contentRect is the area the widgets of the child region are clipped to
+	// as [x,y,w,h] in display coordinates.
+	contentRect mgl.Vec4
+
+	// scrollOffset and scrollOffsetX are how far the widgets of the child
+	// region are scrolled up and to the left in display coordinates.
+	scrollOffset  float32
+	scrollOffsetX float32
+
+	// the layout of the parent, which is restored by EndChild()
+	widgetCursorDC        mgl.Vec3
+	nextRowCursorOffsetDC float32
+	contentWidthDC        float32
+	indentLevel           int
+}
+
+// currentChild returns the innermost child region being built or nil if no
+// child region was started.
+func (wnd *Window) currentChild() *childState {
+	if len(wnd.childStack) == 0 {
+		return nil
+	}
+	return wnd.childStack[len(wnd.childStack)-1]
+}
+
+// BeginChild starts a child region that the widgets built until EndChild()
+// is called are placed in. The region is clipped and scrolled on its own with
+// the mouse wheel and its scroll bar, and it has a border. The size is in
+// screen-normalized space; a width of zero fills the rest of the row and a
+// height of zero fills the rest of the window. The scroll position is stored
+// in the window with the id as the key like TreeNode() stores its state.
+// Child regions can be nested.
+func (wnd *Window) BeginChild(id string, widthS, heightS float32) {
+	wnd.BeginChildAdv(id, widthS, heightS, ChildOptions{Border: true, ShowScrollBar: true})
+}
+
+// BeginChildAdv starts a child region like BeginChild() does with the options
+// applied.
+func (wnd *Window) BeginChildAdv(id string, widthS, heightS float32, options ChildOptions) {
+	ui := wnd.Owner
+
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.ChildMargin[0]
+	pos[1] -= wnd.Style.ChildMargin[2]
+
+	// calculate the size of the region
+	marginW := wnd.Style.ChildMargin[0] + wnd.Style.ChildMargin[1]
+	widthDC, heightDC := ui.ScreenToDisplay(widthS, heightS)
+	if widthS <= 0.0 {
+		widthDC = wnd.takeRowWidth() - marginW
+	} else {
+		widthDC = wnd.clampWidgetWidthToReqW(widthDC+marginW) - marginW
+		wnd.requestedItemWidthMinDC = 0.0
+		wnd.requestedItemWidthMaxDC = 0.0
+	}
+	if heightS <= 0.0 {
+		heightDC = pos[1] - wnd.contentBottomDC() - wnd.Style.ChildMargin[3]
+	}
+	if widthDC < 0.0 {
+		widthDC = 0.0
+	}
+	if heightDC < 0.0 {
+		heightDC = 0.0
+	}
+
+	child := new(childState)
+	child.id = id
+	child.options = options
+	child.rect = mgl.Vec4{pos[0], pos[1], widthDC, heightDC}
+	child.contentRect = child.rect
+	if options.Border {
+		border := wnd.Style.ChildBorderWidth
+		child.contentRect = mgl.Vec4{pos[0] + border, pos[1] - border, widthDC - border*2.0, heightDC - border*2.0}
+	}
+	if options.ShowScrollBar {
+		child.contentRect[2] -= wnd.Style.ScrollBarWidth
+	}
+	if options.ShowHorizontalScrollBar {
+		child.contentRect[3] -= wnd.Style.ScrollBarWidth
+	}
+
+	// load the scroll position
+	scrollX, _ := wnd.getStoredInt(id + "#scrollX")
+	scrollY, _ := wnd.getStoredInt(id + "#scrollY")
+	child.scrollOffsetX = float32(scrollX)
+	child.scrollOffset = float32(scrollY)
+
+	// draw the background under the widgets
+	cmd := wnd.getLastCmd()
+	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+widthDC, pos[1]-heightDC, wnd.Style.ChildBgColor, defaultTextureSampler, ui.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	wnd.beginRegion(child)
+}
+
+// beginRegion saves the layout of the parent in the child state and starts a
+// new layout that places the widgets inside of the child's contentRect and
+// clips them to it.
+func (wnd *Window) beginRegion(child *childState) {
+	child.widgetCursorDC = wnd.widgetCursorDC
+	child.nextRowCursorOffsetDC = wnd.nextRowCursorOffsetDC
+	child.contentWidthDC = wnd.contentWidthDC
+	child.indentLevel = wnd.indentLevel
+	wnd.childStack = append(wnd.childStack, child)
+	wnd.pushClipRect(child.contentRect)
+
+	wnd.widgetCursorDC = mgl.Vec3{wnd.Style.WindowPadding[0], child.scrollOffset - wnd.Style.WindowPadding[2], 0}
+	wnd.nextRowCursorOffsetDC = 0
+	wnd.contentWidthDC = 0
+	wnd.indentLevel = 0
+}
+
+// endRegion goes back to the layout of the parent of the innermost region
+// started with beginRegion() and returns the region along with the height
+// and width all of its widgets need, calculated like the window does.
+func (wnd *Window) endRegion() (*childState, float32, float32) {
+	child := wnd.currentChild()
+	totalControlHeightDC := -wnd.widgetCursorDC[1] + wnd.nextRowCursorOffsetDC + child.scrollOffset + wnd.Style.WindowPadding[3]
+	totalControlWidthDC := wnd.contentWidthDC + wnd.Style.WindowPadding[1]
+
+	wnd.popClipRect()
+	wnd.childStack = wnd.childStack[:len(wnd.childStack)-1]
+	wnd.widgetCursorDC = child.widgetCursorDC
+	wnd.nextRowCursorOffsetDC = child.nextRowCursorOffsetDC
+	wnd.contentWidthDC = child.contentWidthDC
+	wnd.indentLevel = child.indentLevel
+	return child, totalControlHeightDC, totalControlWidthDC
+}
+
+// EndChild ends the child region started last with BeginChild() and places
+// it in the parent like any other widget.
+func (wnd *Window) EndChild() {
+	// the cells of tables aren't ended here
+	if child := wnd.currentChild(); child == nil || child.isCell {
+		return
+	}
+	ui := wnd.Owner
+
+	// go back to the layout of the parent
+	child, totalControlHeightDC, totalControlWidthDC := wnd.endRegion()
+
+	// scroll with the mouse wheel if no widget in the child used it and there
+	// is something to scroll to; holding shift scrolls sideways instead.
+	r := child.rect
+	contentW, contentH := child.contentRect[2], child.contentRect[3]
+	mx, my := wnd.getMousePosition()
+	if mx > r[0] && my > r[1]-r[3] && mx < r[0]+r[2] && my < r[1] && !ui.wheelCaptured {
+		if ui.isShiftDown() {
+			if totalControlWidthDC > contentW {
+				child.scrollOffsetX -= ui.GetScrollWheelDelta(true)
+				ui.wheelCaptured = true
+			}
+		} else if totalControlHeightDC > contentH {
+			child.scrollOffset -= ui.GetScrollWheelDelta(true)
+			ui.wheelCaptured = true
+		}
+	}
+
+	// the scroll bars can scroll the child too
+	border := float32(0.0)
+	if child.options.Border {
+		border = wnd.Style.ChildBorderWidth
+	}
+	vTrack := mgl.Vec4{r[0] + r[2] - border - wnd.Style.ScrollBarWidth, r[1] - border, wnd.Style.ScrollBarWidth, contentH}
+	hTrack := mgl.Vec4{r[0] + border, r[1] - r[3] + border + wnd.Style.ScrollBarWidth, contentW, wnd.Style.ScrollBarWidth}
+	if child.options.ShowScrollBar {
+		wnd.scrollBarTrackBehavior(child.id+"#vscroll", vTrack, true, totalControlHeightDC, contentH, &child.scrollOffset)
+	}
+	if child.options.ShowHorizontalScrollBar {
+		wnd.scrollBarTrackBehavior(child.id+"#hscroll", hTrack, false, totalControlWidthDC, contentW, &child.scrollOffsetX)
+	}
+
+	// keep the scroll position within the widgets and store it
+	if overflow := totalControlHeightDC - contentH; child.scrollOffset > overflow {
+		child.scrollOffset = overflow
+	}
+	if child.scrollOffset < 0.0 {
+		child.scrollOffset = 0.0
+	}
+	if overflow := totalControlWidthDC - contentW; child.scrollOffsetX > overflow {
+		child.scrollOffsetX = overflow
+	}
+	if child.scrollOffsetX < 0.0 {
+		child.scrollOffsetX = 0.0
+	}
+	wnd.setStoredInt(child.id+"#scrollY", int(child.scrollOffset))
+	wnd.setStoredInt(child.id+"#scrollX", int(child.scrollOffsetX))
+
+	// draw the scroll bars and the border on top of the widgets
+	cmd := wnd.getLastCmd()
+	if child.options.ShowScrollBar {
+		wnd.drawScrollBar(cmd, vTrack, true, totalControlHeightDC, child.scrollOffset)
+	}
+	if child.options.ShowHorizontalScrollBar {
+		wnd.drawScrollBar(cmd, hTrack, false, totalControlWidthDC, child.scrollOffsetX)
+	}
+	if child.options.Border {
+		combos, indexes, fc := cmd.DrawRectOutlineDC(r[0], r[1], r[0]+r[2], r[1]-r[3], border, wnd.Style.ChildBorderColor, defaultTextureSampler, ui.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+	}
+
+	// advance the cursor of the parent past the child region
+	wnd.addCursorHorizontalDelta(r[2] + wnd.Style.ChildMargin[0] + wnd.Style.ChildMargin[1])
+	wnd.setNextRowCursorOffset(r[3] + wnd.Style.ChildMargin[2] + wnd.Style.ChildMargin[3])
+}
+
+// contentBottomDC returns the y display coordinate of the bottom of the area
+// widgets are placed in, which is the inside of the current child region or
+// the window above its horizontal scroll bar.
+func (wnd *Window) contentBottomDC() float32 {
+	if child := wnd.currentChild(); child != nil {
+		return child.contentRect[1] - child.contentRect[3] + wnd.Style.WindowPadding[3]
+	}
+	_, y, _, h := wnd.GetFrameSize()
+	return y - h + wnd.horizontalScrollBarHeight() + wnd.Style.WindowPadding[3]
+}
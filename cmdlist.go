@@ -4,6 +4,8 @@
 package eweygewey
 
 import (
+	"math"
+
 	mgl "github.com/go-gl/mathgl/mgl32"
 	graphics "github.com/tbogdala/fizzle/graphicsprovider"
 )
@@ -15,7 +17,7 @@ type cmdList struct {
 	indexBuffer  []uint32         // vbo elements
 	faceCount    uint32           // face count
 	indexTracker uint32           // the offset for the next set of indexes when adding new faces
-	clipRect     mgl.Vec4         // clip rect [x1,y1,x2,y2] top-left to bottom-right
+	clipRect     mgl.Vec4         // clip rect [x,y,w,h] top-left corner and size
 	textureID    graphics.Texture // texture to bind
 
 	isCustom     bool   // is this a custom render command?
@@ -82,6 +84,15 @@ func (cmds *cmdList) PrefixFaces(comboFloats []float32, indexInts []uint32, face
 // Coordinate parameters should be passed in display coordinates.
 // Returns the combo vertex data, element indexes and face count for the rect.
 func (cmds *cmdList) DrawRectFilledDC(tlx, tly, brx, bry float32, color mgl.Vec4, textureIndex uint32, whitePixelUv mgl.Vec4) ([]float32, []uint32, uint32) {
+	return cmds.DrawRectGradientDC(tlx, tly, brx, bry, color, color, color, color, textureIndex, whitePixelUv)
+}
+
+// DrawRectGradientDC draws a rectangle in the user interface with a color for
+// each corner (top-left, top-right, bottom-left and bottom-right) that gets
+// blended across the rectangle.
+// Coordinate parameters should be passed in display coordinates.
+// Returns the combo vertex data, element indexes and face count for the rect.
+func (cmds *cmdList) DrawRectGradientDC(tlx, tly, brx, bry float32, tlColor, trColor, blColor, brColor mgl.Vec4, textureIndex uint32, whitePixelUv mgl.Vec4) ([]float32, []uint32, uint32) {
 	uv := whitePixelUv
 
 	verts := [8]float32{
@@ -102,6 +113,13 @@ func (cmds *cmdList) DrawRectFilledDC(tlx, tly, brx, bry float32, color mgl.Vec4
 		uv[2], uv[3],
 	}
 
+	colors := [4]mgl.Vec4{
+		blColor,
+		brColor,
+		tlColor,
+		trColor,
+	}
+
 	comboBuffer := []float32{}
 	indexBuffer := []uint32{}
 
@@ -119,7 +137,7 @@ func (cmds *cmdList) DrawRectFilledDC(tlx, tly, brx, bry float32, color mgl.Vec4
 		comboBuffer = append(comboBuffer, float32(textureIndex))
 
 		// add the color
-		comboBuffer = append(comboBuffer, color[:]...)
+		comboBuffer = append(comboBuffer, colors[i][:]...)
 	}
 
 	// define the polys with 2 faces (6 indexes)
@@ -131,6 +149,38 @@ func (cmds *cmdList) DrawRectFilledDC(tlx, tly, brx, bry float32, color mgl.Vec4
 	return comboBuffer, indexBuffer, 2
 }
 
+// DrawRectOutlineDC draws the outline of a rectangle in the user interface
+// with lines of the given thickness drawn inside the rectangle.
+// Coordinate parameters should be passed in display coordinates.
+// Returns the combo vertex data, element indexes and face count for the outline.
+func (cmds *cmdList) DrawRectOutlineDC(tlx, tly, brx, bry, thickness float32, color mgl.Vec4, textureIndex uint32, whitePixelUv mgl.Vec4) ([]float32, []uint32, uint32) {
+	comboBuffer := []float32{}
+	indexBuffer := []uint32{}
+	var faceCount uint32
+
+	// top, bottom, left and right edges
+	edges := [4][4]float32{
+		{tlx, tly, brx, tly - thickness},
+		{tlx, bry + thickness, brx, bry},
+		{tlx, tly - thickness, tlx + thickness, bry + thickness},
+		{brx - thickness, tly - thickness, brx, bry + thickness},
+	}
+
+	for _, e := range edges {
+		combos, indexes, fc := cmds.DrawRectFilledDC(e[0], e[1], e[2], e[3], color, textureIndex, whitePixelUv)
+
+		// offset the indexes past the vertices already added
+		startIndex := uint32(len(comboBuffer) / VertexStride)
+		for _, idx := range indexes {
+			indexBuffer = append(indexBuffer, startIndex+idx)
+		}
+		comboBuffer = append(comboBuffer, combos...)
+		faceCount += fc
+	}
+
+	return comboBuffer, indexBuffer, faceCount
+}
+
 func (cmds *cmdList) drawTreeNodeIcon(isOpen bool, tlx, tly, brx, bry float32, color mgl.Vec4, textureIndex uint32, whitePixelUv mgl.Vec4) ([]float32, []uint32, uint32) {
 	comboBuffer := []float32{}
 	indexBuffer := []uint32{}
@@ -197,3 +247,58 @@ func (cmds *cmdList) drawTreeNodeIcon(isOpen bool, tlx, tly, brx, bry float32, c
 	// return the vertex data
 	return comboBuffer, indexBuffer, 1
 }
+
+// drawTriangleDC draws a solid triangle with the corners given in display
+// coordinates in counter-clockwise order.
+// Returns the combo vertex data, element indexes and face count for the triangle.
+func (cmds *cmdList) drawTriangleDC(x1, y1, x2, y2, x3, y3 float32, color mgl.Vec4, textureIndex uint32, whitePixelUv mgl.Vec4) ([]float32, []uint32, uint32) {
+	comboBuffer := []float32{}
+	verts := [6]float32{x1, y1, x2, y2, x3, y3}
+	for i := 0; i < 3; i++ {
+		comboBuffer = append(comboBuffer, verts[i*2], verts[i*2+1])
+		comboBuffer = append(comboBuffer, whitePixelUv[0], whitePixelUv[1])
+		comboBuffer = append(comboBuffer, float32(textureIndex))
+		comboBuffer = append(comboBuffer, color[:]...)
+	}
+	return comboBuffer, []uint32{0, 1, 2}, 1
+}
+
+// drawCrossIcon draws an 'X' with lines of the given thickness that go from
+// corner to corner of the rectangle, which is given in display coordinates.
+// Returns the combo vertex data, element indexes and face count for the icon.
+func (cmds *cmdList) drawCrossIcon(tlx, tly, brx, bry, thickness float32, color mgl.Vec4, textureIndex uint32, whitePixelUv mgl.Vec4) ([]float32, []uint32, uint32) {
+	comboBuffer := []float32{}
+	indexBuffer := []uint32{}
+
+	// each line is a quad made by pushing the diagonal out to both sides
+	t := thickness * 0.5
+	lines := [2][4]float32{
+		{tlx, tly, brx, bry},
+		{tlx, bry, brx, tly},
+	}
+	for _, l := range lines {
+		dx, dy := l[2]-l[0], l[3]-l[1]
+		length := float32(math.Sqrt(float64(dx*dx + dy*dy)))
+		if length == 0.0 {
+			continue
+		}
+		nx, ny := -dy/length*t, dx/length*t
+		verts := [8]float32{
+			l[0] + nx, l[1] + ny,
+			l[0] - nx, l[1] - ny,
+			l[2] + nx, l[3] + ny,
+			l[2] - nx, l[3] - ny,
+		}
+
+		startIndex := uint32(len(comboBuffer) / VertexStride)
+		for i := 0; i < 4; i++ {
+			comboBuffer = append(comboBuffer, verts[i*2], verts[i*2+1])
+			comboBuffer = append(comboBuffer, whitePixelUv[0], whitePixelUv[1])
+			comboBuffer = append(comboBuffer, float32(textureIndex))
+			comboBuffer = append(comboBuffer, color[:]...)
+		}
+		indexBuffer = append(indexBuffer, startIndex, startIndex+1, startIndex+2, startIndex+1, startIndex+3, startIndex+2)
+	}
+
+	return comboBuffer, indexBuffer, uint32(len(indexBuffer) / 3)
+}
@@ -0,0 +1,459 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"fmt"
+	"strconv"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// colorPickerWindowID is the ID of the popup used by ColorPicker4.
+	colorPickerWindowID = "eweygewey#colorpicker"
+
+	// colorPickerRecentMax is the number of recently picked colors to remember.
+	colorPickerRecentMax = 8
+)
+
+var (
+	// the colors of the squares in the checkerboard drawn behind the alpha bar
+	checkerLightColor = ColorIToV(204, 204, 204, 255)
+	checkerDarkColor  = ColorIToV(128, 128, 128, 255)
+
+	// the colors at the edges of the six sections of the hue bar
+	hueBarColors = [7]mgl.Vec4{
+		ColorIToV(255, 0, 0, 255),
+		ColorIToV(255, 255, 0, 255),
+		ColorIToV(0, 255, 0, 255),
+		ColorIToV(0, 255, 255, 255),
+		ColorIToV(0, 0, 255, 255),
+		ColorIToV(255, 0, 255, 255),
+		ColorIToV(255, 0, 0, 255),
+	}
+)
+
+// colorPickerState is the state of the popup that picks the color for a
+// ColorPicker4 widget.
+type colorPickerState struct {
+	// OwnerID is the ID of the ColorPicker4 widget that opened the popup.
+	OwnerID string
+
+	// Color is the color being picked.
+	Color mgl.Vec4
+
+	// Original is the color when the popup was opened.
+	Original mgl.Vec4
+
+	// Hue, Saturation and Value are the HSV components of Color, which are
+	// kept so that the hue isn't lost when the color becomes a shade of gray.
+	Hue, Saturation, Value float32
+
+	// HexText is the text in the hex editbox.
+	HexText string
+
+	// Changed is set when the popup changes Color and cleared when the
+	// owner takes the new color.
+	Changed bool
+
+	// Err is the error from building the popup, which the owner returns the
+	// next time it's built.
+	Err error
+}
+
+// setColor changes the color being picked and updates the HSV components.
+func (picker *colorPickerState) setColor(color mgl.Vec4) {
+	picker.Color = color
+	h, s, v := RGBToHSV(color[0], color[1], color[2])
+	if s > 0.0 && v > 0.0 {
+		picker.Hue = h
+	}
+	if v > 0.0 {
+		picker.Saturation = s
+	}
+	picker.Value = v
+}
+
+// setHSV changes the color being picked to the HSV components, keeping the alpha.
+func (picker *colorPickerState) setHSV(h, s, v float32) {
+	picker.Hue, picker.Saturation, picker.Value = h, s, v
+	picker.Color[0], picker.Color[1], picker.Color[2] = HSVToRGB(h, s, v)
+}
+
+// colorToHex returns the color as a RRGGBBAA hex string.
+func colorToHex(color mgl.Vec4) string {
+	var components [4]int
+	for i := range components {
+		components[i] = int(ClipF32(0.0, 1.0, color[i])*255.0 + 0.5)
+	}
+	return fmt.Sprintf("%02X%02X%02X%02X", components[0], components[1], components[2], components[3])
+}
+
+// hexToColor parses a RRGGBB or RRGGBBAA hex string. If there is no alpha in
+// the string, the alpha of the fallback color is used. Returns false if the
+// string couldn't be parsed.
+func hexToColor(hex string, fallback mgl.Vec4) (mgl.Vec4, bool) {
+	if len(hex) != 6 && len(hex) != 8 {
+		return fallback, false
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fallback, false
+	}
+	if len(hex) == 6 {
+		value = value<<8 | uint64(ClipF32(0.0, 1.0, fallback[3])*255.0+0.5)
+	}
+	return ColorIToV(int(value>>24&0xFF), int(value>>16&0xFF), int(value>>8&0xFF), int(value&0xFF)), true
+}
+
+// openColorPicker opens the color picker popup below the owner's swatch, or
+// moves it there if it's already open for another widget. Pressing the mouse
+// on the swatch doesn't close the popup so that the swatch can toggle it.
+func (ui *Manager) openColorPicker(ownerID string, ownerRect mgl.Vec4, color mgl.Vec4) {
+	if picker := ui.colorPicker; picker != nil && picker.Color != picker.Original {
+		ui.rememberColor(picker.Color)
+	}
+
+	picker := new(colorPickerState)
+	picker.OwnerID = ownerID
+	picker.Original = color
+	picker.setColor(color)
+	ui.colorPicker = picker
+
+	p := ui.openPopupAt(colorPickerWindowID, ownerRect[0], ownerRect[1]-ownerRect[3])
+	p.Anchor = mgl.Vec2{ownerRect[0], ownerRect[1] - ownerRect[3]}
+	p.OwnerRect = ownerRect
+}
+
+// rememberColor adds the color to the front of the recently picked colors.
+func (ui *Manager) rememberColor(color mgl.Vec4) {
+	recent := []mgl.Vec4{color}
+	for _, c := range ui.recentColors {
+		if c != color && len(recent) < colorPickerRecentMax {
+			recent = append(recent, c)
+		}
+	}
+	ui.recentColors = recent
+}
+
+// buildColorPicker is the BuildCallback for the color picker popup.
+func (ui *Manager) buildColorPicker(wnd *Window) {
+	picker := ui.colorPicker
+	if picker == nil {
+		return
+	}
+
+	// Escape puts the original color back and closes the popup
+	if ui.consumeKey(EweyKeyEscape) {
+		if picker.Color != picker.Original {
+			picker.setColor(picker.Original)
+			picker.Changed = true
+		}
+		ui.ClosePopup(colorPickerWindowID)
+		return
+	}
+
+	oldColor := picker.Color
+	picker.Err = wnd.colorPickerContents(picker)
+	if picker.Color != oldColor {
+		picker.Changed = true
+	}
+}
+
+// colorPickerContents builds the widgets of the color picker popup.
+func (wnd *Window) colorPickerContents(picker *colorPickerState) error {
+	const id = colorPickerWindowID
+	cmd := wnd.getLastCmd()
+	size := wnd.Style.ColorPickerSize
+	barW := wnd.Style.ColorPickerBarWidth
+	white := mgl.Vec4{1.0, 1.0, 1.0, 1.0}
+	black := mgl.Vec4{0.0, 0.0, 0.0, 1.0}
+	clear := mgl.Vec4{0.0, 0.0, 0.0, 0.0}
+
+	// the saturation/value square is drawn as a gradient from white to the
+	// hue with a gradient from clear to black on top of it.
+	pos, dragging, rx, ry := wnd.colorArea(id+"#sv", size, size)
+	if dragging {
+		picker.setHSV(picker.Hue, rx, 1.0-ry)
+	}
+	r, g, b := HSVToRGB(picker.Hue, 1.0, 1.0)
+	hueColor := mgl.Vec4{r, g, b, 1.0}
+	combos, indexes, fc := cmd.DrawRectGradientDC(pos[0], pos[1], pos[0]+size, pos[1]-size, white, hueColor, white, hueColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+	combos, indexes, fc = cmd.DrawRectGradientDC(pos[0], pos[1], pos[0]+size, pos[1]-size, clear, clear, black, black, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+	cursorX := pos[0] + picker.Saturation*size
+	cursorY := pos[1] - (1.0-picker.Value)*size
+	combos, indexes, fc = cmd.DrawRectOutlineDC(cursorX-4, cursorY+4, cursorX+4, cursorY-4, 2.0, white, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	// the hue bar is drawn as six gradients between the primary and secondary colors
+	pos, dragging, _, ry = wnd.colorArea(id+"#hue", barW, size)
+	if dragging {
+		picker.setHSV(ry, picker.Saturation, picker.Value)
+	}
+	sectionH := size / 6.0
+	for i := 0; i < 6; i++ {
+		top := pos[1] - float32(i)*sectionH
+		combos, indexes, fc = cmd.DrawRectGradientDC(pos[0], top, pos[0]+barW, top-sectionH, hueBarColors[i], hueBarColors[i], hueBarColors[i+1], hueBarColors[i+1], defaultTextureSampler, wnd.Owner.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+	}
+	cursorY = pos[1] - picker.Hue*size
+	combos, indexes, fc = cmd.DrawRectOutlineDC(pos[0], cursorY+2, pos[0]+barW, cursorY-2, 1.0, white, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	// the alpha bar is drawn as a gradient from the opaque color to clear
+	// on top of a checkerboard so that the transparency is visible.
+	pos, dragging, _, ry = wnd.colorArea(id+"#alpha", barW, size)
+	if dragging {
+		picker.Color[3] = 1.0 - ry
+	}
+	checkerSize := barW * 0.5
+	for row := 0; float32(row)*checkerSize < size; row++ {
+		top := pos[1] - float32(row)*checkerSize
+		bottom := top - checkerSize
+		if bottom < pos[1]-size {
+			bottom = pos[1] - size
+		}
+		for col := 0; col < 2; col++ {
+			checkerColor := checkerLightColor
+			if (row+col)%2 == 1 {
+				checkerColor = checkerDarkColor
+			}
+			left := pos[0] + float32(col)*checkerSize
+			combos, indexes, fc = cmd.DrawRectFilledDC(left, top, left+checkerSize, bottom, checkerColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			cmd.AddFaces(combos, indexes, fc)
+		}
+	}
+	opaque := picker.Color
+	opaque[3] = 1.0
+	transparent := picker.Color
+	transparent[3] = 0.0
+	combos, indexes, fc = cmd.DrawRectGradientDC(pos[0], pos[1], pos[0]+barW, pos[1]-size, opaque, opaque, transparent, transparent, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+	cursorY = pos[1] - (1.0-picker.Color[3])*size
+	combos, indexes, fc = cmd.DrawRectOutlineDC(pos[0], cursorY+2, pos[0]+barW, cursorY-2, 1.0, white, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	// show the original color, which can be clicked to go back to it, next
+	// to the new color and the hex value of the new color.
+	wnd.StartRow()
+	pressed, err := wnd.ColorButton(id+"#original", picker.Original)
+	if err != nil {
+		return err
+	}
+	if pressed {
+		picker.setColor(picker.Original)
+	}
+	_, err = wnd.ColorButton(id+"#current", picker.Color)
+	if err != nil {
+		return err
+	}
+
+	hexID := id + "#hex"
+	ate := wnd.Owner.getActiveTextEditor()
+	if ate == nil || ate.ID != hexID {
+		picker.HexText = colorToHex(picker.Color)
+	}
+	result, err := wnd.EditboxAdv(hexID, &picker.HexText, EditboxOptions{Filter: EditboxFilterHex, MaxLength: 8})
+	if err != nil {
+		return err
+	}
+	if result&EditboxChanged == EditboxChanged {
+		if color, okay := hexToColor(picker.HexText, picker.Color); okay {
+			picker.setColor(color)
+		}
+	}
+
+	// show the recently picked colors
+	if len(wnd.Owner.recentColors) > 0 {
+		wnd.StartRow()
+		for i, color := range wnd.Owner.recentColors {
+			pressed, err = wnd.ColorButton(fmt.Sprintf("%s#recent%d", id, i), color)
+			if err != nil {
+				return err
+			}
+			if pressed {
+				picker.setColor(color)
+			}
+		}
+	}
+
+	return nil
+}
+
+// colorArea reserves a w by h area at the cursor for one of the gradients in
+// the color picker. It returns the top-left corner of the area and whether or
+// not the mouse is dragging in it. If it is, the position of the mouse in the
+// area is returned as ratios in [0 .. 1] from the top-left corner.
+func (wnd *Window) colorArea(id string, w, h float32) (mgl.Vec3, bool, float32, float32) {
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.SliderMargin[0]
+	pos[1] -= wnd.Style.SliderMargin[2]
+
+	// advance the cursor for the size of the area
+	wnd.addCursorHorizontalDelta(w + wnd.Style.SliderMargin[0] + wnd.Style.SliderMargin[1])
+	wnd.setNextRowCursorOffset(h + wnd.Style.SliderMargin[2] + wnd.Style.SliderMargin[3])
+
+	// test to see if the mouse was pressed inside the area
+	dragging := false
+	lmbStatus := wnd.Owner.GetMouseButtonAction(0)
+	if lmbStatus != MouseUp {
+		if wnd.Owner.GetActiveInputID() == id {
+			dragging = true
+		} else {
+			mx, my := wnd.getMouseDownPosition(0)
+			if mx > pos[0] && my > pos[1]-h && mx < pos[0]+w && my < pos[1] {
+				dragging = wnd.Owner.SetActiveInputID(id)
+			}
+		}
+	}
+	if !dragging {
+		return pos, false, 0.0, 0.0
+	}
+
+	mx, my := wnd.getMousePosition()
+	return pos, true, ClipF32(0.0, 1.0, (mx-pos[0])/w), ClipF32(0.0, 1.0, (pos[1]-my)/h)
+}
+
+// swatchSize returns the width and height of the color swatches, which are
+// squares as high as a slider.
+func (wnd *Window) swatchSize() (float32, error) {
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return 0.0, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+	_, dimY, _ := font.GetRenderSize("0.0")
+	return dimY + wnd.Style.SliderPadding[2] + wnd.Style.SliderPadding[3], nil
+}
+
+// colorSwatch draws a square of the given size showing the color opaque on the
+// left half and with its alpha on the right half.
+func (wnd *Window) colorSwatch(color mgl.Vec4, size float32) {
+	cmd := wnd.getLastCmd()
+
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.SliderMargin[0]
+	pos[1] -= wnd.Style.SliderMargin[2]
+
+	opaque := color
+	opaque[3] = 1.0
+	half := size * 0.5
+	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+half, pos[1]-size, opaque, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+	combos, indexes, fc = cmd.DrawRectFilledDC(pos[0]+half, pos[1], pos[0]+size, pos[1]-size, color, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	// advance the cursor for the width of the swatch
+	wnd.addCursorHorizontalDelta(size + wnd.Style.SliderMargin[0] + wnd.Style.SliderMargin[1])
+	wnd.setNextRowCursorOffset(size + wnd.Style.SliderMargin[2] + wnd.Style.SliderMargin[3])
+}
+
+// ColorButton draws a swatch of the color that acts like a button. Returns
+// true if it was pressed.
+func (wnd *Window) ColorButton(id string, color mgl.Vec4) (bool, error) {
+	size, err := wnd.swatchSize()
+	if err != nil {
+		return false, err
+	}
+
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.SliderMargin[0]
+	pos[1] -= wnd.Style.SliderMargin[2]
+
+	// keyboard activation presses the button just like a click
+	_, activated := wnd.focusBehavior(id)
+
+	// test to see if the mouse is inside the widget
+	pressed := false
+	buttonTest := wnd.buttonBehavior(id, pos[0], pos[1], size, size)
+	if buttonTest == buttonPressed {
+		pressed = true
+	}
+
+	wnd.colorSwatch(color, size)
+	cmd := wnd.getLastCmd()
+	if buttonTest == buttonHover {
+		combos, indexes, fc := cmd.DrawRectOutlineDC(pos[0], pos[1], pos[0]+size, pos[1]-size, 1.0, wnd.Style.ButtonHoverColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+	}
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], size, size)
+
+	// if we've captured the mouse click event and registered a button press, clear
+	// the tracking data for the mouse button so that we don't get duplicate matches.
+	if pressed {
+		wnd.Owner.ClearMouseButtonAction(0)
+	}
+
+	return pressed || activated, nil
+}
+
+// ColorPicker4 draws a swatch of the color that opens a popup when pressed to
+// pick the color with a saturation/value square, hue and alpha bars, a hex
+// editbox and a palette of recently picked colors. Pressing the mouse outside
+// of the popup closes it and Escape closes it and restores the original color.
+// Returns true if the color was changed.
+func (wnd *Window) ColorPicker4(id string, color *mgl.Vec4) (bool, error) {
+	ui := wnd.Owner
+	changed := false
+
+	// take the color from the popup if it's open for this widget
+	picker := ui.colorPicker
+	isOpen := picker != nil && picker.OwnerID == id
+	var pickerErr error
+	if isOpen {
+		if picker.Changed {
+			*color = picker.Color
+			picker.Changed = false
+			changed = true
+		}
+
+		// the popup is built after this widget, so its error is a frame late
+		pickerErr, picker.Err = picker.Err, nil
+
+		// the popup was closed, so let go of the state
+		if !ui.IsPopupOpen(colorPickerWindowID) {
+			if picker.Color != picker.Original {
+				ui.rememberColor(picker.Color)
+			}
+			ui.colorPicker = nil
+			isOpen = false
+		}
+	}
+
+	size, err := wnd.swatchSize()
+	if err != nil {
+		return changed, err
+	}
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.SliderMargin[0]
+	pos[1] -= wnd.Style.SliderMargin[2]
+
+	pressed, err := wnd.ColorButton(id, *color)
+	if err != nil {
+		return changed, err
+	}
+	if pressed {
+		if isOpen {
+			ui.ClosePopup(colorPickerWindowID)
+			isOpen = false
+		} else {
+			ui.openColorPicker(id, mgl.Vec4{pos[0], pos[1], size, size}, *color)
+			isOpen = true
+		}
+	}
+
+	// size the popup to fit the square and bars
+	if isOpen {
+		widthDC := wnd.Style.ColorPickerSize + 2*wnd.Style.ColorPickerBarWidth + 3*(wnd.Style.SliderMargin[0]+wnd.Style.SliderMargin[1]) +
+			wnd.Style.WindowPadding[0] + wnd.Style.WindowPadding[1]
+		wnd.popup(colorPickerWindowID, false, "", widthDC, ui.buildColorPicker)
+	}
+
+	return changed, pickerErr
+}
@@ -0,0 +1,353 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"fmt"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// comboState is the state of the combo box whose dropdown list is open.
+type comboState struct {
+	// ID is the ID of the combo box widget.
+	ID string
+
+	// Highlighted is the index of the item highlighted by the mouse or the
+	// arrow keys.
+	Highlighted int
+
+	// Scroll is the index of the first item shown in the list.
+	Scroll int
+
+	// Alive is set when the combo box is built so that the list closes if
+	// the combo box goes away.
+	Alive bool
+}
+
+// pruneCombo closes the dropdown list if its combo box wasn't built last frame.
+func (ui *Manager) pruneCombo() {
+	if ui.openCombo == nil {
+		return
+	}
+	if !ui.openCombo.Alive {
+		ui.openCombo = nil
+		return
+	}
+	ui.openCombo.Alive = false
+}
+
+// Combo draws a combo box showing the selected item which opens a dropdown
+// list of the items when clicked or activated with the keyboard. The list is
+// drawn on top of all of the windows and closes when an item is picked, the
+// mouse is pressed outside of it or Escape is pressed. While it's open, the
+// arrow keys, Page Up and Page Down highlight an item and Enter picks it.
+// Long lists scroll with the mouse wheel.
+// Returns true if the selected index was changed.
+func (wnd *Window) Combo(id string, selected *int, items []string) (bool, error) {
+	ui := wnd.Owner
+	cmd := wnd.getLastCmd()
+
+	// get the font for the text
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.ComboMargin[0]
+	pos[1] -= wnd.Style.ComboMargin[2]
+
+	// calculate the size necessary for the widget
+	selectedText := ""
+	if *selected >= 0 && *selected < len(items) {
+		selectedText = items[*selected]
+	}
+	_, _, wndWidth, _ := wnd.GetDisplaySize()
+	_, dimY, _ := font.GetRenderSize("0.0")
+	comboW := wnd.clampWidgetWidthToReqW(wndWidth - wnd.widgetCursorDC[0] - wnd.Style.WindowPadding[1])
+	comboW = comboW - wnd.Style.ComboMargin[0] - wnd.Style.ComboMargin[1]
+	comboH := dimY + wnd.Style.ComboPadding[2] + wnd.Style.ComboPadding[3]
+
+	state := ui.openCombo
+	if state != nil && state.ID != id {
+		state = nil
+	}
+	changed := false
+
+	// clicking the box or activating it with the keyboard toggles the list;
+	// while it's open, the list handles Enter and Space itself.
+	var focused, activated bool
+	if state != nil {
+		focused, _ = ui.registerFocusable(id)
+	} else {
+		focused, activated = wnd.focusBehavior(id)
+	}
+	buttonTest := wnd.buttonBehavior(id, pos[0], pos[1], comboW, comboH)
+	toggled := activated || buttonTest == buttonPressed
+	if buttonTest == buttonPressed {
+		wnd.Owner.ClearMouseButtonAction(0)
+		wnd.Owner.setFocusFromMouse(id)
+	}
+
+	// with the list closed, Up and Down change the selection directly
+	if state == nil && focused && len(items) > 0 {
+		newSelection := *selected
+		ui.consumeKeyEvents(func(event KeyPressEvent) bool {
+			if event.IsRune == false && event.KeyCode == EweyKeyUp {
+				newSelection--
+				return true
+			} else if event.IsRune == false && event.KeyCode == EweyKeyDown {
+				newSelection++
+				return true
+			}
+			return false
+		})
+		if newSelection < 0 {
+			newSelection = 0
+		} else if newSelection >= len(items) {
+			newSelection = len(items) - 1
+		}
+		changed = newSelection != *selected
+		*selected = newSelection
+	}
+
+	if toggled {
+		if state != nil {
+			ui.openCombo = nil
+			state = nil
+		} else if len(items) > 0 {
+			state = &comboState{ID: id, Highlighted: *selected}
+			ui.openCombo = state
+		}
+	}
+
+	// render the combo box with the selected item and an arrow on the right
+	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+comboW, pos[1]-comboH, wnd.Style.ComboBgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], comboW, comboH)
+	arrowSize := comboH * 0.25
+	arrowX := pos[0] + comboW - wnd.Style.ComboPadding[1] - arrowSize*2
+	combos, indexes, fc = cmd.drawTreeNodeIcon(true, arrowX, pos[1]-comboH*0.375, arrowX+arrowSize*2, pos[1]-comboH*0.625, wnd.Style.ComboTextColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	textCmd := wnd.addClippedCmd(pos[0], pos[1], arrowX-pos[0], comboH)
+	textPos := pos
+	textPos[0] += wnd.Style.ComboPadding[0]
+	textPos[1] -= wnd.Style.ComboPadding[2]
+	renderData := font.CreateText(textPos, wnd.Style.ComboTextColor, selectedText)
+	textCmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+	wnd.addNewCmd()
+
+	// advance the cursor for the width of the widget
+	wnd.addCursorHorizontalDelta(comboW + wnd.Style.ComboMargin[0] + wnd.Style.ComboMargin[1])
+	wnd.setNextRowCursorOffset(comboH + wnd.Style.ComboMargin[2] + wnd.Style.ComboMargin[3])
+
+	if state == nil {
+		return changed, nil
+	}
+	state.Alive = true
+
+	// close the list if focus moved to another widget or the items are gone
+	if ui.focusedID != id || len(items) == 0 {
+		ui.openCombo = nil
+		return changed, nil
+	}
+
+	listChanged, err := wnd.comboList(state, selected, items, mgl.Vec4{pos[0], pos[1], comboW, comboH})
+	return changed || listChanged, err
+}
+
+// comboList handles the input for the open dropdown list of a combo box and
+// draws it in the overlay below the box, or above it if there's no room.
+// box is the combo box as [x,y,w,h] in display coordinates.
+func (wnd *Window) comboList(state *comboState, selected *int, items []string, box mgl.Vec4) (bool, error) {
+	ui := wnd.Owner
+	font := ui.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	// size the list to show up to ComboMaxItems rows
+	_, dimY, _ := font.GetRenderSize("0.0")
+	rowH := dimY + wnd.Style.ComboPadding[2] + wnd.Style.ComboPadding[3]
+	visibleRows := len(items)
+	if wnd.Style.ComboMaxItems > 0 && visibleRows > wnd.Style.ComboMaxItems {
+		visibleRows = wnd.Style.ComboMaxItems
+	}
+
+	// open the list below the box unless there's more room above it and
+	// drop rows that won't fit on the screen
+	_, screenH := ui.GetResolution()
+	roomBelow := box[1] - box[3]
+	roomAbove := float32(screenH) - box[1]
+	openAbove := float32(visibleRows)*rowH > roomBelow && roomAbove > roomBelow
+	room := roomBelow
+	if openAbove {
+		room = roomAbove
+	}
+	if fit := int(room / rowH); visibleRows > fit {
+		visibleRows = fit
+	}
+	if visibleRows < 1 {
+		visibleRows = 1
+	}
+
+	maxScroll := len(items) - visibleRows
+	listW := box[2]
+	listH := float32(visibleRows) * rowH
+	listX := box[0]
+	listY := box[1] - box[3]
+	if openAbove {
+		listY = box[1] + listH
+	}
+
+	closeList := false
+	changed := false
+	pick := func(index int) {
+		changed = *selected != index
+		*selected = index
+		closeList = true
+	}
+
+	// handle the keyboard
+	ui.consumeKeyEvents(func(event KeyPressEvent) bool {
+		if event.IsRune {
+			return false
+		}
+		switch event.KeyCode {
+		case EweyKeyUp:
+			state.Highlighted--
+		case EweyKeyDown:
+			state.Highlighted++
+		case EweyKeyPageUp:
+			state.Highlighted -= visibleRows
+		case EweyKeyPageDown:
+			state.Highlighted += visibleRows
+		case EweyKeyHome:
+			state.Highlighted = 0
+		case EweyKeyEnd:
+			state.Highlighted = len(items) - 1
+		case EweyKeyEnter:
+			if state.Highlighted >= 0 && state.Highlighted < len(items) {
+				pick(state.Highlighted)
+			}
+		case EweyKeyEscape:
+			closeList = true
+		default:
+			return false
+		}
+
+		if state.Highlighted < 0 {
+			state.Highlighted = 0
+		} else if state.Highlighted >= len(items) {
+			state.Highlighted = len(items) - 1
+		}
+
+		// keep the highlighted item in view
+		if state.Highlighted < state.Scroll {
+			state.Scroll = state.Highlighted
+		} else if state.Highlighted >= state.Scroll+visibleRows {
+			state.Scroll = state.Highlighted - visibleRows + 1
+		}
+		return true
+	})
+
+	// handle the mouse; the overlay blocks the mouse for the windows so the
+	// position is taken from the Manager directly.
+	mx, my := ui.GetMousePosition()
+	mouseInside := mx > listX && my > listY-listH && mx < listX+listW && my < listY
+	if mouseInside {
+		wheelDelta := ui.GetScrollWheelDelta(true)
+		if wheelDelta != 0.0 {
+			rows := int(wheelDelta / rowH)
+			if rows == 0 && wheelDelta > 0.0 {
+				rows = 1
+			} else if rows == 0 {
+				rows = -1
+			}
+			state.Scroll -= rows
+		}
+		ui.wheelCaptured = true
+	}
+	if state.Scroll > maxScroll {
+		state.Scroll = maxScroll
+	}
+	if state.Scroll < 0 {
+		state.Scroll = 0
+	}
+
+	hoveredRow := -1
+	if mouseInside {
+		hoveredRow = state.Scroll + int((listY-my)/rowH)
+		if hoveredRow >= len(items) {
+			hoveredRow = -1
+		}
+		if dx, dy := ui.GetMousePositionDelta(); hoveredRow >= 0 && (dx != 0.0 || dy != 0.0) {
+			state.Highlighted = hoveredRow
+		}
+	}
+
+	lmbStatus := ui.GetMouseButtonAction(0)
+	// picking an item right after opening the list can be seen as a double click
+	if mouseInside && (lmbStatus == MouseClick || lmbStatus == MouseDoubleClick) && hoveredRow >= 0 {
+		pick(hoveredRow)
+		ui.ClearMouseButtonAction(0)
+	} else if ui.lmbPressed {
+		// pressing outside of the list closes it; pressing the box toggles
+		// it when the click arrives.
+		mdx, mdy := ui.GetMouseDownPosition(0)
+		insideList := mdx > listX && mdy > listY-listH && mdx < listX+listW && mdy < listY
+		insideBox := mdx > box[0] && mdy > box[1]-box[3] && mdx < box[0]+box[2] && mdy < box[1]
+		if !insideList && !insideBox {
+			closeList = true
+		}
+	}
+
+	if closeList {
+		ui.openCombo = nil
+		return changed, nil
+	}
+
+	// draw the list in the overlay
+	cmd := ui.addOverlayCmd(listX, listY, listW, listH)
+	combos, indexes, fc := cmd.DrawRectFilledDC(listX, listY, listX+listW, listY-listH, wnd.Style.ComboBgColor, defaultTextureSampler, ui.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	textW := listW
+	if maxScroll > 0 {
+		textW -= wnd.Style.ScrollBarWidth
+	}
+	for row := 0; row < visibleRows; row++ {
+		index := state.Scroll + row
+		rowY := listY - float32(row)*rowH
+		if index == state.Highlighted {
+			combos, indexes, fc = cmd.DrawRectFilledDC(listX, rowY, listX+textW, rowY-rowH, wnd.Style.ComboHoverColor, defaultTextureSampler, ui.whitePixelUv)
+			cmd.AddFaces(combos, indexes, fc)
+		}
+		textPos := mgl.Vec3{listX + wnd.Style.ComboPadding[0], rowY - wnd.Style.ComboPadding[2], 0}
+		renderData := font.CreateText(textPos, wnd.Style.ComboTextColor, items[index])
+		cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+	}
+
+	// show where the visible rows are in a long list
+	if maxScroll > 0 {
+		sbX := listX + listW - wnd.Style.ScrollBarWidth
+		combos, indexes, fc = cmd.DrawRectFilledDC(sbX, listY, listX+listW, listY-listH, wnd.Style.ScrollBarBgColor, defaultTextureSampler, ui.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+
+		sbCursorWidth := wnd.Style.ScrollBarCursorWidth
+		if sbCursorWidth > wnd.Style.ScrollBarWidth {
+			sbCursorWidth = wnd.Style.ScrollBarWidth
+		}
+		sbCursorOffX := (wnd.Style.ScrollBarWidth - sbCursorWidth) / 2.0
+		sbCursorH := listH * float32(visibleRows) / float32(len(items))
+		sbCursorY := listY - listH*float32(state.Scroll)/float32(len(items))
+		combos, indexes, fc = cmd.DrawRectFilledDC(sbX+sbCursorOffX, sbCursorY, sbX+sbCursorOffX+sbCursorWidth, sbCursorY-sbCursorH, wnd.Style.ScrollBarCursorColor, defaultTextureSampler, ui.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+	}
+
+	return changed, nil
+}
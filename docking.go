@@ -0,0 +1,704 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"fmt"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// these constants describe how a DockNode divides its area.
+const (
+	DockSplitNone       = iota // the node is a leaf holding docked windows
+	DockSplitHorizontal        // the children are side by side, the first on the left
+	DockSplitVertical          // the children are stacked, the first on top
+)
+
+// these constants are the places a window can be docked relative to its
+// target with Manager.DockWindow().
+const (
+	DockCenter = iota // as a tab of the target
+	DockLeft
+	DockRight
+	DockTop
+	DockBottom
+)
+
+// DockSpaceID is the ID of the WindowDrawData that holds the splitter bars
+// between docked windows, which is drawn under all of the windows.
+const DockSpaceID = "eweygewey#dockspace"
+
+// dockMinSizeDC is the smallest size in display coordinates that dragging a
+// splitter bar can make a dock node.
+const dockMinSizeDC = 32.0
+
+// dockScreenRatio is the fraction of the screen a window docked at the edge
+// of the screen takes up.
+const dockScreenRatio = 0.25
+
+// DockNode is a node in a tree of docked windows. A leaf node shows one of
+// the windows docked in it at a time with tabs in the title bar to switch
+// between them; the other nodes split their area between two children with
+// a splitter bar that can be dragged to resize them.
+type DockNode struct {
+	// ID is the ID of the node, which is unique within the Manager.
+	ID string
+
+	// Parent is the node this node is a child of or nil for a root node.
+	Parent *DockNode
+
+	// Children are the two nodes the area is split between if Split isn't
+	// DockSplitNone.
+	Children [2]*DockNode
+
+	// Split is one of the DockSplit* constants.
+	Split int
+
+	// Ratio is the fraction of the area given to the first child.
+	Ratio float32
+
+	// Windows are the windows docked in a leaf node.
+	Windows []*Window
+
+	// Selected is the index of the window shown in a leaf node.
+	Selected int
+
+	// Location is the upper left hand corner of the node in screen-normalized
+	// coordinates; it's updated along with the size each frame.
+	Location mgl.Vec2
+
+	// Width is how wide the node is in screen-normalized space.
+	Width float32
+
+	// Height is how tall the node is in screen-normalized space.
+	Height float32
+
+	// fillsScreen is set for the root node of the windows docked at the edges
+	// of the screen, which takes up the screen below the main menu bar.
+	fillsScreen bool
+
+	// central is set for the leaf of the screen's root node that holds the
+	// space between the windows docked at the edges of the screen; it stays
+	// even when no windows are docked in it.
+	central bool
+}
+
+// dockTarget is a place a dragged window can be dropped to dock it.
+type dockTarget struct {
+	window  *Window  // the target window or nil for the edge of the screen
+	side    int      // one of the Dock* constants
+	rect    mgl.Vec4 // the drop target as [x,y,w,h] in display coordinates
+	preview mgl.Vec4 // the area the window would take up as [x,y,w,h]
+}
+
+// IsLeaf returns true if the node holds windows instead of being split.
+func (node *DockNode) IsLeaf() bool {
+	return node.Split == DockSplitNone
+}
+
+// getRectDC returns the area of the node as [x,y,w,h] in display coordinates.
+func (node *DockNode) getRectDC(ui *Manager) mgl.Vec4 {
+	x, y := ui.ScreenToDisplay(node.Location[0], node.Location[1])
+	w, h := ui.ScreenToDisplay(node.Width, node.Height)
+	return mgl.Vec4{x, y, w, h}
+}
+
+// GetDockRoots returns the root nodes of the trees of docked windows.
+func (ui *Manager) GetDockRoots() []*DockNode {
+	return ui.dockRoots
+}
+
+// GetDockNode returns the leaf node the window is docked in or nil if the
+// window is floating.
+func (wnd *Window) GetDockNode() *DockNode {
+	return wnd.dockNode
+}
+
+// dockRoot returns the root node of the tree the window is docked in or nil
+// if the window is floating.
+func (wnd *Window) dockRoot() *DockNode {
+	node := wnd.dockNode
+	if node == nil {
+		return nil
+	}
+	for node.Parent != nil {
+		node = node.Parent
+	}
+	return node
+}
+
+// newDockNode creates a new leaf node with a unique ID.
+func (ui *Manager) newDockNode() *DockNode {
+	ui.dockNodeCount++
+	node := new(DockNode)
+	node.ID = fmt.Sprintf("eweygewey#docknode%d", ui.dockNodeCount)
+	node.Ratio = 0.5
+	return node
+}
+
+// DockWindow docks the window at the side of the target window, splitting the
+// node the target is docked in, or adds it as a tab of the target with
+// DockCenter. If the target is nil, the window is docked at that edge of the
+// screen or between the windows docked at the edges with DockCenter. Windows
+// remember where they were floating and UndockWindow() puts them back.
+func (ui *Manager) DockWindow(wnd *Window, target *Window, side int) {
+	if wnd == target {
+		return
+	}
+	ui.UndockWindow(wnd)
+
+	// find the node to dock the window in or next to
+	var node *DockNode
+	ratio := float32(0.5)
+	if target == nil {
+		node = ui.screenDockNode()
+		ratio = dockScreenRatio
+		if central := node.findCentral(); side == DockCenter && central != nil {
+			node = central
+		}
+	} else if target.dockNode != nil {
+		node = target.dockNode
+	} else {
+		node = ui.newDockNode()
+		x, y, w, h := target.GetFrameSize()
+		node.Location[0], node.Location[1] = ui.DisplayToScreen(x, y)
+		node.Width, node.Height = ui.DisplayToScreen(w, h)
+		ui.dockRoots = append(ui.dockRoots, node)
+		ui.addDockedWindow(node, target)
+	}
+
+	if side == DockCenter {
+		ui.addDockedWindow(node, wnd)
+		return
+	}
+	leaf := ui.newDockNode()
+	ui.addDockedWindow(leaf, wnd)
+	ui.splitDockNode(node, leaf, side, ratio)
+}
+
+// UndockWindow removes the window from the node it's docked in and puts it
+// back where it was floating before it was docked.
+func (ui *Manager) UndockWindow(wnd *Window) {
+	node := wnd.dockNode
+	if node == nil {
+		return
+	}
+
+	for i, w := range node.Windows {
+		if w == wnd {
+			node.Windows = append(node.Windows[:i], node.Windows[i+1:]...)
+			if node.Selected > i || node.Selected >= len(node.Windows) {
+				node.Selected--
+			}
+			break
+		}
+	}
+	if node.Selected < 0 {
+		node.Selected = 0
+	}
+
+	wnd.dockNode = nil
+	wnd.hidden = false
+	wnd.Location = wnd.floatingLocation
+	wnd.Width = wnd.floatingWidth
+	wnd.Height = wnd.floatingHeight
+
+	if len(node.Windows) == 0 && !node.central {
+		ui.removeDockNode(node)
+	} else if len(node.Windows) == 0 && node.fillsScreen {
+		ui.removeDockRoot(node)
+	}
+}
+
+// addDockedWindow adds the window as the selected tab of the leaf node and
+// remembers where it was floating.
+func (ui *Manager) addDockedWindow(node *DockNode, wnd *Window) {
+	wnd.floatingLocation = wnd.Location
+	wnd.floatingWidth = wnd.Width
+	wnd.floatingHeight = wnd.Height
+	wnd.dockNode = node
+	node.Windows = append(node.Windows, wnd)
+	node.Selected = len(node.Windows) - 1
+}
+
+// screenDockNode returns the root node of the windows docked at the edges of
+// the screen, creating it if needed.
+func (ui *Manager) screenDockNode() *DockNode {
+	for _, root := range ui.dockRoots {
+		if root.fillsScreen {
+			return root
+		}
+	}
+	root := ui.newDockNode()
+	root.fillsScreen = true
+	root.central = true
+	ui.dockRoots = append(ui.dockRoots, root)
+	ui.layoutDocks()
+	return root
+}
+
+// findCentral returns the central leaf in the tree under the node or nil if
+// there is none.
+func (node *DockNode) findCentral() *DockNode {
+	if node.central {
+		return node
+	}
+	if node.IsLeaf() {
+		return nil
+	}
+	for _, child := range node.Children {
+		if central := child.findCentral(); central != nil {
+			return central
+		}
+	}
+	return nil
+}
+
+// replaceDockNode puts the replacement where the node is in the tree.
+func (ui *Manager) replaceDockNode(node, replacement *DockNode) {
+	replacement.Parent = node.Parent
+	if node.Parent != nil {
+		for i, child := range node.Parent.Children {
+			if child == node {
+				node.Parent.Children[i] = replacement
+			}
+		}
+		return
+	}
+
+	// a new root takes over the area of the old one
+	for i, root := range ui.dockRoots {
+		if root == node {
+			ui.dockRoots[i] = replacement
+		}
+	}
+	replacement.Location = node.Location
+	replacement.Width = node.Width
+	replacement.Height = node.Height
+	replacement.fillsScreen = node.fillsScreen
+	node.fillsScreen = false
+}
+
+// splitDockNode splits the area of the node with the leaf, which gets the
+// ratio of the area at the side of the node.
+func (ui *Manager) splitDockNode(node, leaf *DockNode, side int, ratio float32) {
+	split := ui.newDockNode()
+	ui.replaceDockNode(node, split)
+	node.Parent = split
+	leaf.Parent = split
+
+	switch side {
+	case DockLeft, DockTop:
+		split.Children = [2]*DockNode{leaf, node}
+		split.Ratio = ratio
+	default:
+		split.Children = [2]*DockNode{node, leaf}
+		split.Ratio = 1.0 - ratio
+	}
+	if side == DockLeft || side == DockRight {
+		split.Split = DockSplitHorizontal
+	} else {
+		split.Split = DockSplitVertical
+	}
+	ui.layoutDocks()
+}
+
+// removeDockNode removes the empty leaf node from its tree and lets the other
+// child of its parent take the parent's place.
+func (ui *Manager) removeDockNode(node *DockNode) {
+	parent := node.Parent
+	if parent == nil {
+		ui.removeDockRoot(node)
+		return
+	}
+
+	sibling := parent.Children[0]
+	if sibling == node {
+		sibling = parent.Children[1]
+	}
+	ui.replaceDockNode(parent, sibling)
+
+	// nothing is docked at the edges of the screen anymore
+	if sibling.fillsScreen && sibling.central && len(sibling.Windows) == 0 {
+		ui.removeDockRoot(sibling)
+	}
+	ui.layoutDocks()
+}
+
+// removeDockRoot removes the root node from the slice of root nodes.
+func (ui *Manager) removeDockRoot(root *DockNode) {
+	for i, r := range ui.dockRoots {
+		if r == root {
+			ui.dockRoots = append(ui.dockRoots[:i], ui.dockRoots[i+1:]...)
+			return
+		}
+	}
+}
+
+// layoutDocks sizes the dock nodes and the windows docked in them. Only the
+// selected window of each leaf node is shown.
+func (ui *Manager) layoutDocks() {
+	_, h := ui.GetResolution()
+	for _, root := range ui.dockRoots {
+		if root.fillsScreen {
+			_, menuH := ui.DisplayToScreen(0.0, ui.GetMainMenuBarHeight())
+			if h == 0 {
+				menuH = 0.0
+			}
+			root.Location = mgl.Vec2{0.0, 1.0 - menuH}
+			root.Width = 1.0
+			root.Height = 1.0 - menuH
+		}
+		ui.layoutDockNode(root)
+	}
+}
+
+// layoutDockNode sizes the children of the node or the windows docked in it.
+func (ui *Manager) layoutDockNode(node *DockNode) {
+	if node.IsLeaf() {
+		for i, wnd := range node.Windows {
+			wnd.hidden = i != node.Selected
+			wnd.Location[0] = node.Location[0]
+			wnd.Location[1] = node.Location[1]
+			wnd.Width = node.Width
+			if wnd.ShowScrollBar {
+				sbWidth, _ := ui.DisplayToScreen(wnd.Style.ScrollBarWidth, 0.0)
+				wnd.Width -= sbWidth
+			}
+			_, decorationH := ui.DisplayToScreen(0.0, wnd.titleBarHeight()+wnd.menuBarHeight()+wnd.horizontalScrollBarHeight())
+			wnd.Height = node.Height - decorationH
+			if wnd.Height < 0.0 {
+				wnd.Height = 0.0
+			}
+		}
+		return
+	}
+
+	first, second := node.Children[0], node.Children[1]
+	splitterW, splitterH := ui.DisplayToScreen(ui.DockStyle.DockSplitterSize, ui.DockStyle.DockSplitterSize)
+	if node.Split == DockSplitHorizontal {
+		firstW := (node.Width - splitterW) * node.Ratio
+		first.Location = node.Location
+		first.Width, first.Height = firstW, node.Height
+		second.Location = mgl.Vec2{node.Location[0] + firstW + splitterW, node.Location[1]}
+		second.Width, second.Height = node.Width-firstW-splitterW, node.Height
+	} else {
+		firstH := (node.Height - splitterH) * node.Ratio
+		first.Location = node.Location
+		first.Width, first.Height = node.Width, firstH
+		second.Location = mgl.Vec2{node.Location[0], node.Location[1] - firstH - splitterH}
+		second.Width, second.Height = node.Width, node.Height-firstH-splitterH
+	}
+	ui.layoutDockNode(first)
+	ui.layoutDockNode(second)
+}
+
+// splitterRectDC returns the splitter bar of a split node as [x,y,w,h] in
+// display coordinates.
+func (ui *Manager) splitterRectDC(node *DockNode) mgl.Vec4 {
+	r := node.Children[0].getRectDC(ui)
+	size := ui.DockStyle.DockSplitterSize
+	if node.Split == DockSplitHorizontal {
+		return mgl.Vec4{r[0] + r[2], r[1], size, r[3]}
+	}
+	return mgl.Vec4{r[0], r[1] - r[3], r[2], size}
+}
+
+// updateDocks is called at the start of each frame after the mouse owner has
+// been picked to drag the splitter bars, lay out the docked windows and build
+// the splitter bars.
+func (ui *Manager) updateDocks() {
+	ui.dockCmds = ui.dockCmds[:0]
+	if len(ui.dockRoots) == 0 {
+		return
+	}
+
+	ui.layoutDocks()
+	w, h := ui.GetResolution()
+	cmd := newCmdList()
+	cmd.clipRect = mgl.Vec4{0, float32(h), float32(w), float32(h)}
+	for _, root := range ui.dockRoots {
+		ui.dockSplitterBehavior(cmd, root)
+	}
+	ui.dockCmds = append(ui.dockCmds, cmd)
+	ui.layoutDocks()
+}
+
+// dockSplitterBehavior lets the splitter bars of the node and its children be
+// dragged to resize them and draws them.
+func (ui *Manager) dockSplitterBehavior(cmd *cmdList, node *DockNode) {
+	if node.IsLeaf() {
+		return
+	}
+
+	r := ui.splitterRectDC(node)
+	inside := func(x, y float32) bool {
+		return x > r[0] && y > r[1]-r[3] && x < r[0]+r[2] && y < r[1]
+	}
+
+	// the splitter bars are between the windows, so they only get the mouse
+	// when no window does
+	mx, my := ui.GetMousePosition()
+	free := ui.mouseOwner == nil && ui.topModalIndex() < 0
+	active := ui.GetActiveInputID() == node.ID
+	if free && ui.lmbPressed && inside(ui.GetMouseDownPosition(0)) && ui.SetActiveInputID(node.ID) {
+		active = true
+	}
+
+	if active && ui.GetMouseButtonAction(0) == MouseDown {
+		nr := node.getRectDC(ui)
+		size := ui.DockStyle.DockSplitterSize
+		var ratio, minRatio float32
+		if node.Split == DockSplitHorizontal {
+			ratio = (mx - nr[0] - size*0.5) / (nr[2] - size)
+			minRatio = dockMinSizeDC / (nr[2] - size)
+		} else {
+			ratio = (nr[1] - my - size*0.5) / (nr[3] - size)
+			minRatio = dockMinSizeDC / (nr[3] - size)
+		}
+		if minRatio > 0.5 {
+			minRatio = 0.5
+		}
+		if ratio < minRatio {
+			ratio = minRatio
+		} else if ratio > 1.0-minRatio {
+			ratio = 1.0 - minRatio
+		}
+		node.Ratio = ratio
+	}
+
+	color := ui.DockStyle.DockSplitterColor
+	if active || (free && inside(mx, my) && ui.GetActiveInputID() == "") {
+		color = ui.DockStyle.DockActiveColor
+		if node.Split == DockSplitHorizontal {
+			ui.cursorShape = CursorResizeEW
+		} else {
+			ui.cursorShape = CursorResizeNS
+		}
+	}
+	combos, indexes, fc := cmd.DrawRectFilledDC(r[0], r[1], r[0]+r[2], r[1]-r[3], color, defaultTextureSampler, ui.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	ui.dockSplitterBehavior(cmd, node.Children[0])
+	ui.dockSplitterBehavior(cmd, node.Children[1])
+}
+
+// titleBarContains returns true if the display coordinate is inside of the
+// title bar of the window.
+func (wnd *Window) titleBarContains(x, y float32) bool {
+	wx, wy, ww, _ := wnd.GetFrameSize()
+	return x > wx && x < wx+ww && y < wy && y > wy-wnd.titleBarHeight()
+}
+
+// dockTabWidths returns the widths of the tabs in the title bar of a docked
+// window in display coordinates or nil if there's only one window docked.
+func (wnd *Window) dockTabWidths() []float32 {
+	node := wnd.dockNode
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if node == nil || len(node.Windows) < 2 || font == nil {
+		return nil
+	}
+	widths := make([]float32, len(node.Windows))
+	for i, w := range node.Windows {
+		dimX, _, _ := font.GetRenderSize(w.GetTitleString())
+		widths[i] = dimX + wnd.Style.TabPadding[0] + wnd.Style.TabPadding[1]
+	}
+	return widths
+}
+
+// dockTabAt returns the index of the tab at the display x coordinate in the
+// title bar of a docked window or -1 if there isn't one.
+func (wnd *Window) dockTabAt(x float32) int {
+	tabX, _, _, _ := wnd.GetFrameSize()
+	for i, w := range wnd.dockTabWidths() {
+		if x > tabX && x < tabX+w {
+			return i
+		}
+		tabX += w + wnd.Style.TabSpacing
+	}
+	return -1
+}
+
+// buildDockTabs draws the tabs of the windows docked in the same node in the
+// title bar of a docked window.
+func (wnd *Window) buildDockTabs(cmd *cmdList, x, y, titleBarHeight float32) {
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	node := wnd.dockNode
+	tabX := x
+	for i, w := range wnd.dockTabWidths() {
+		bgColor := wnd.Style.TabColor
+		if node.Windows[i] == wnd {
+			bgColor = wnd.Style.TabActiveColor
+		}
+		combos, indexes, fc := cmd.DrawRectFilledDC(tabX, y, tabX+w, y-titleBarHeight, bgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+
+		textPos := mgl.Vec3{tabX + wnd.Style.TabPadding[0], y - wnd.Style.TitleBarPadding[2], 0}
+		renderData := font.CreateText(textPos, wnd.Style.TabTextColor, node.Windows[i].Title)
+		cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+		tabX += w + wnd.Style.TabSpacing
+	}
+}
+
+// dockBehavior handles the title bar of a docked window. Pressing a tab shows
+// its window and dragging the title bar far enough undocks the window so that
+// it can be moved and docked somewhere else.
+func (wnd *Window) dockBehavior() {
+	ui := wnd.Owner
+	node := wnd.dockNode
+	if ui.GetMouseButtonAction(0) != MouseDown {
+		return
+	}
+	mdx, mdy := wnd.getMouseDownPosition(0)
+	if !wnd.titleBarContains(mdx, mdy) {
+		return
+	}
+	if !ui.SetActiveInputID(node.ID) && ui.GetActiveInputID() != node.ID {
+		return
+	}
+
+	// pressing another tab shows its window, which then gets the mouse
+	if i := wnd.dockTabAt(mdx); i >= 0 && node.Windows[i] != wnd {
+		node.Selected = i
+		ui.mouseOwner = node.Windows[i]
+		return
+	}
+
+	mx, my := wnd.getMousePosition()
+	titleBarHeight := wnd.titleBarHeight()
+	if mx-mdx < titleBarHeight && mdx-mx < titleBarHeight && my-mdy < titleBarHeight && mdy-my < titleBarHeight {
+		return
+	}
+
+	// undock the window and keep its title bar under the mouse while it's
+	// dragged; it can be docked again by dropping it on a drop target.
+	frameX, frameY, _, _ := wnd.GetFrameSize()
+	offsetX, offsetY := mdx-frameX, frameY-mdy
+	ui.UndockWindow(wnd)
+	_, _, frameW, _ := wnd.GetFrameSize()
+	if offsetX > frameW {
+		offsetX = frameW * 0.5
+	}
+	wnd.Location[0], wnd.Location[1] = ui.DisplayToScreen(mx-offsetX, my+offsetY)
+	ui.activeInputID = wnd.ID
+	ui.dockDragWindow = wnd
+}
+
+// dockTargets returns the places the dragged window can be docked with the
+// mouse at the display coordinate: the sides and center of the window under
+// the mouse and the edges of the screen.
+func (ui *Manager) dockTargets(dragged *Window, mx, my float32) []dockTarget {
+	var targets []dockTarget
+	size := ui.DockStyle.DockTargetSize
+	gap := size * 0.25
+	w, h := ui.GetResolution()
+	screenW, screenH := float32(w), float32(h)-ui.GetMainMenuBarHeight()
+
+	// the window under the mouse
+	var target *Window
+	for i := len(ui.windows) - 1; i >= 0; i-- {
+		wnd := ui.windows[i]
+		if wnd != dragged && wnd.isShown() && wnd.ContainsPosition(mx, my) {
+			if wnd.IsDockable {
+				target = wnd
+			}
+			break
+		}
+	}
+	if target != nil {
+		var area mgl.Vec4
+		if target.dockNode != nil {
+			area = target.dockNode.getRectDC(ui)
+		} else {
+			x, y, w, h := target.GetFrameSize()
+			area = mgl.Vec4{x, y, w, h}
+		}
+		cx, cy := area[0]+area[2]*0.5, area[1]-area[3]*0.5
+		offsets := [5][2]float32{{0, 0}, {-size - gap, 0}, {size + gap, 0}, {0, size + gap}, {0, -size - gap}}
+		for side, o := range offsets {
+			rect := mgl.Vec4{cx + o[0] - size*0.5, cy + o[1] + size*0.5, size, size}
+			targets = append(targets, dockTarget{target, side, rect, dockPreview(area, side, 0.5)})
+		}
+	}
+
+	// the edges of the screen
+	screen := mgl.Vec4{0, screenH, screenW, screenH}
+	edges := [4]mgl.Vec4{
+		{gap, (screenH + size) * 0.5, size, size},
+		{screenW - gap - size, (screenH + size) * 0.5, size, size},
+		{(screenW - size) * 0.5, screenH - gap, size, size},
+		{(screenW - size) * 0.5, gap + size, size, size},
+	}
+	for i, rect := range edges {
+		side := DockLeft + i
+		targets = append(targets, dockTarget{nil, side, rect, dockPreview(screen, side, dockScreenRatio)})
+	}
+	return targets
+}
+
+// dockPreview returns the part of the area as [x,y,w,h] a window docked at
+// the side of it would take up.
+func dockPreview(area mgl.Vec4, side int, ratio float32) mgl.Vec4 {
+	switch side {
+	case DockLeft:
+		area[2] *= ratio
+	case DockRight:
+		area[0] += area[2] * (1.0 - ratio)
+		area[2] *= ratio
+	case DockTop:
+		area[3] *= ratio
+	case DockBottom:
+		area[1] -= area[3] * (1.0 - ratio)
+		area[3] *= ratio
+	}
+	return area
+}
+
+// updateDockDrop is called at the end of each frame. While a window is dragged
+// by its title bar, it shows the drop targets in the overlay and docks the
+// window if it's released on one.
+func (ui *Manager) updateDockDrop() {
+	dragged := ui.dockDragWindow
+	if dragged == nil {
+		return
+	}
+
+	mx, my := ui.GetMousePosition()
+	targets := ui.dockTargets(dragged, mx, my)
+	hovered := -1
+	for i, t := range targets {
+		if mx > t.rect[0] && my > t.rect[1]-t.rect[3] && mx < t.rect[0]+t.rect[2] && my < t.rect[1] {
+			hovered = i
+		}
+	}
+
+	if ui.GetMouseButtonAction(0) != MouseDown {
+		ui.dockDragWindow = nil
+		if hovered >= 0 {
+			ui.DockWindow(dragged, targets[hovered].window, targets[hovered].side)
+		}
+		return
+	}
+
+	// the drop targets don't block the mouse, so they're added to the
+	// overlay without an overlay rect
+	w, h := ui.GetResolution()
+	cmd := newCmdList()
+	cmd.clipRect = mgl.Vec4{0, float32(h), float32(w), float32(h)}
+	if hovered >= 0 {
+		p := targets[hovered].preview
+		combos, indexes, fc := cmd.DrawRectFilledDC(p[0], p[1], p[0]+p[2], p[1]-p[3], ui.DockStyle.DockPreviewColor, defaultTextureSampler, ui.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+	}
+	for i, t := range targets {
+		color := ui.DockStyle.DockTargetColor
+		if i == hovered {
+			color = ui.DockStyle.DockActiveColor
+		}
+		combos, indexes, fc := cmd.DrawRectFilledDC(t.rect[0], t.rect[1], t.rect[0]+t.rect[2], t.rect[1]-t.rect[3], color, defaultTextureSampler, ui.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+	}
+	ui.overlayCmds = append(ui.overlayCmds, cmd)
+}
@@ -0,0 +1,133 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+	graphics "github.com/tbogdala/fizzle/graphicsprovider"
+)
+
+// these constants describe the layout of a vertex in the DrawCmd.Vertices
+// slice, measured in float32 values.
+const (
+	VertexPositionOffset     = 0 // x, y in display coordinates
+	VertexUVOffset           = 2 // s, t
+	VertexTextureIndexOffset = 4 // index into DrawData.Textures
+	VertexColorOffset        = 5 // r, g, b, a
+	VertexStride             = 9 // total number of floats per vertex
+)
+
+// OverlayID is the ID of the WindowDrawData that holds the overlay, which
+// is drawn on top of all of the windows.
+const OverlayID = "eweygewey#overlay"
+
+// DrawCmd is a backend-agnostic view of a single draw command built by a window.
+type DrawCmd struct {
+	// ClipRect is the area the command should be clipped to, specified as
+	// [x, y, w, h] where (x,y) is the top-left corner in display coordinates.
+	ClipRect mgl.Vec4
+
+	// Vertices is the combo vertex data for the command with VertexStride
+	// floats per vertex.
+	Vertices []float32
+
+	// Indices are the triangle element indexes into Vertices.
+	Indices []uint32
+
+	// FaceCount is the number of triangles described by Indices.
+	FaceCount uint32
+
+	// IsCustom indicates that this command has no vertex data and that
+	// OnCustomDraw should be called instead.
+	IsCustom bool
+
+	// OnCustomDraw is the client callback set by Window.Custom().
+	OnCustomDraw func()
+}
+
+// WindowDrawData holds the draw commands for a window in the order they
+// should be rendered.
+type WindowDrawData struct {
+	// ID is the ID of the window that built the commands.
+	ID string
+
+	// Commands is the slice of draw commands for the window.
+	Commands []DrawCmd
+}
+
+// DrawData is the full set of data needed to render the user interface
+// for a frame with any rendering backend.
+type DrawData struct {
+	// Width and Height are the resolution of the user interface; vertex
+	// positions are in display coordinates with the origin in the lower left.
+	Width, Height int32
+
+	// Textures maps the texture index stored in each vertex to a texture.
+	// Index 0 is always the font texture and the rest are the textures
+	// added with Manager.AddTextureToStack().
+	Textures []graphics.Texture
+
+	// Windows is the slice of windows to draw, back to front. If windows are
+	// docked, the splitter bars between them come first with the ID
+	// DockSpaceID. The main menu bar with the ID MainMenuBarID and then the
+	// open popups follow the windows and if anything was drawn in the
+	// overlay, it is the last entry and has the ID OverlayID.
+	Windows []WindowDrawData
+}
+
+// DrawData returns the draw commands built by the last call to Construct().
+// The slices in the returned value reference the Manager's internal buffers
+// and are only valid until the next call to Construct().
+func (ui *Manager) DrawData() DrawData {
+	var dd DrawData
+	dd.Width, dd.Height = ui.GetResolution()
+
+	// the font texture is always bound to the first sampler
+	var fontTexture graphics.Texture
+	font := ui.GetFont(DefaultStyle.FontName)
+	if font != nil {
+		fontTexture = font.Texture
+	}
+	dd.Textures = make([]graphics.Texture, 0, len(ui.textureStack)+1)
+	dd.Textures = append(dd.Textures, fontTexture)
+	dd.Textures = append(dd.Textures, ui.textureStack...)
+
+	dd.Windows = make([]WindowDrawData, 0, len(ui.windows)+len(ui.popups)+3)
+	if len(ui.dockCmds) > 0 {
+		dd.Windows = append(dd.Windows, newWindowDrawData(DockSpaceID, ui.dockCmds))
+	}
+	for _, w := range ui.windows {
+		dd.Windows = append(dd.Windows, newWindowDrawData(w.ID, w.cmds))
+	}
+	if ui.mainMenuBar != nil {
+		dd.Windows = append(dd.Windows, newWindowDrawData(MainMenuBarID, ui.mainMenuBar.cmds))
+	}
+	for _, p := range ui.popups {
+		if len(p.Window.cmds) > 0 {
+			dd.Windows = append(dd.Windows, newWindowDrawData(p.ID, p.Window.cmds))
+		}
+	}
+	if len(ui.overlayCmds) > 0 {
+		dd.Windows = append(dd.Windows, newWindowDrawData(OverlayID, ui.overlayCmds))
+	}
+
+	return dd
+}
+
+// newWindowDrawData creates the WindowDrawData for the command lists.
+func newWindowDrawData(id string, cmds []*cmdList) WindowDrawData {
+	wdd := WindowDrawData{ID: id}
+	wdd.Commands = make([]DrawCmd, 0, len(cmds))
+	for _, cmd := range cmds {
+		wdd.Commands = append(wdd.Commands, DrawCmd{
+			ClipRect:     cmd.clipRect,
+			Vertices:     cmd.comboBuffer,
+			Indices:      cmd.indexBuffer,
+			FaceCount:    cmd.faceCount,
+			IsCustom:     cmd.isCustom,
+			OnCustomDraw: cmd.onCustomDraw,
+		})
+	}
+	return wdd
+}
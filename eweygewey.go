@@ -4,6 +4,8 @@
 package eweygewey
 
 import (
+	"math"
+
 	mgl "github.com/go-gl/mathgl/mgl32"
 )
 
@@ -15,9 +17,23 @@ const (
 	MouseDoubleClick = 4
 )
 
+// constants for the shapes of the mouse cursor that the user interface asks
+// for through Manager.SetCursorShape
+const (
+	CursorArrow      = 0
+	CursorResizeEW   = 1
+	CursorResizeNS   = 2
+	CursorResizeNWSE = 3
+	CursorResizeNESW = 4
+)
+
 // Style defines parameters to the drawing functions that control the way
 // the widgets are organized and drawn.
 type Style struct {
+	AxisXColor           mgl.Vec4 // label color for the X (or first) component of vector widgets
+	AxisYColor           mgl.Vec4 // label color for the Y (or second) component of vector widgets
+	AxisZColor           mgl.Vec4 // label color for the Z (or third) component of vector widgets
+	AxisWColor           mgl.Vec4 // label color for the W (or fourth) component of vector widgets
 	ButtonColor          mgl.Vec4 // button background color
 	ButtonHoverColor     mgl.Vec4 // button background color with mouse hovering
 	ButtonActiveColor    mgl.Vec4 // button background color when clicked
@@ -29,6 +45,24 @@ type Style struct {
 	CheckboxCursorWidth  float32  // checkbox inner check cursor size
 	CheckboxMargin       mgl.Vec4 // [left,right,top,bottom] margin values for checkbox
 	CheckboxPadding      mgl.Vec4 // [left,right,top,bottom] padding values for checkbox
+	ChildBgColor         mgl.Vec4 // background color of child regions
+	ChildBorderColor     mgl.Vec4 // color of the border around child regions
+	ChildBorderWidth     float32  // width of the border around child regions in pixels
+	ChildMargin          mgl.Vec4 // [left,right,top,bottom] margin values for child regions
+	ColorPickerSize      float32  // width and height of the saturation/value square of the color picker
+	ColorPickerBarWidth  float32  // width of the hue and alpha bars of the color picker
+	ComboBgColor         mgl.Vec4 // combo box and dropdown list background color
+	ComboHoverColor      mgl.Vec4 // background color of the highlighted item in a dropdown list
+	ComboTextColor       mgl.Vec4 // combo box text color
+	ComboMargin          mgl.Vec4 // [left,right,top,bottom] margin values for combo boxes
+	ComboPadding         mgl.Vec4 // [left,right,top,bottom] padding values for combo boxes and their items
+	ComboMaxItems        int      // the number of items a dropdown list shows before scrolling
+	DockActiveColor      mgl.Vec4 // color of a hovered or dragged splitter bar and the hovered drop target
+	DockPreviewColor     mgl.Vec4 // color of the area a dragged window would be docked in
+	DockSplitterColor    mgl.Vec4 // color of the splitter bars between docked windows
+	DockTargetColor      mgl.Vec4 // color of the drop targets shown while dragging a window
+	DockSplitterSize     float32  // the width of the splitter bars between docked windows
+	DockTargetSize       float32  // the width and height of the drop targets
 	EditboxBgColor       mgl.Vec4 // Editbox background color
 	EditboxActiveColor   mgl.Vec4 // Editbox background color when clicked
 	EditboxCursorColor   mgl.Vec4 // color for the editbox cursor
@@ -36,11 +70,28 @@ type Style struct {
 	EditboxBlinkDuration float32  // how long the cursor is visible during a blink (in seconds)
 	EditboxBlinkInterval float32  // how many seconds between the start of the cursor blink (in seconds)
 	EditboxTextColor     mgl.Vec4 // Editbox text color
+	EditboxHintColor     mgl.Vec4 // Editbox text color for the placeholder text
+	EditboxSelectedColor mgl.Vec4 // Editbox background color for selected text
 	EditboxMargin        mgl.Vec4 // [left,right,top,bottom] margin values for Editbox
 	EditboxPadding       mgl.Vec4 // [left,right,top,bottom] padding values for Editbox
+	FocusColor           mgl.Vec4 // color of the outline drawn around the widget with keyboard focus
+	FocusBorderWidth     float32  // width of the keyboard focus outline in pixels
 	FontName             string   // font name to use by default
 	ImageMargin          mgl.Vec4 // margin for the image widgets
 	IndentSpacing        float32  // the amount of pixels to indent
+	MenuBarBgColor       mgl.Vec4 // background color of menu bars
+	MenuDisabledColor    mgl.Vec4 // text color of disabled menu items
+	MenuHoverColor       mgl.Vec4 // background color of the highlighted menu item
+	MenuShortcutColor    mgl.Vec4 // text color of the shortcut hints of menu items
+	MenuTextColor        mgl.Vec4 // text color of menu items
+	MenuPadding          mgl.Vec4 // [left,right,top,bottom] padding values for menu items
+	PopupBgColor         mgl.Vec4 // background color of popups and menus
+	PopupDimColor        mgl.Vec4 // color drawn over the user interface under a modal popup
+	PopupWidth           float32  // the width of popups
+	ResizeBorderWidth    float32  // the width of the edges of resizable windows that can be dragged
+	ResizeGripColor      mgl.Vec4 // color of the grip in the corner of resizable windows
+	ResizeGripHoverColor mgl.Vec4 // color of the resize grip with mouse hovering or dragging
+	ResizeGripSize       float32  // the width and height of the resize grip
 	ScrollBarCursorColor mgl.Vec4 // the color of the cursor of the scroll bar
 	ScrollBarBgColor     mgl.Vec4 // the color of the background of the scroll bar
 	ScrollBarWidth       float32  // the width of the scroll bar
@@ -56,6 +107,19 @@ type Style struct {
 	SliderPadding        mgl.Vec4 // padding for the slider text strings
 	SliderTextColor      mgl.Vec4 // slider text color
 	SliderCursorWidth    float32  // slider cursor width
+	TabActiveColor       mgl.Vec4 // background color of the selected tab
+	TabColor             mgl.Vec4 // background color of the tabs that aren't selected
+	TabHoverColor        mgl.Vec4 // background color of a tab with mouse hovering
+	TabTextColor         mgl.Vec4 // text color of tabs
+	TabMargin            mgl.Vec4 // [left,right,top,bottom] margin values for tab bars
+	TabPadding           mgl.Vec4 // [left,right,top,bottom] padding values for tabs
+	TabSpacing           float32  // the space between tabs
+	TableBorderColor     mgl.Vec4 // color of the lines between the columns and rows of tables
+	TableBorderWidth     float32  // width of the lines between the columns and rows of tables
+	TableHeaderColor     mgl.Vec4 // background color of the header row of tables
+	TableHoverColor      mgl.Vec4 // background color of a sortable table header with mouse hovering
+	TableRowColor        mgl.Vec4 // background color of table rows
+	TableRowAltColor     mgl.Vec4 // background color of every other table row
 	TextColor            mgl.Vec4 // text color
 	TextMargin           mgl.Vec4 // margin for text widgets
 	TitleBarPadding      mgl.Vec4 // padding for the title bar of the window
@@ -110,6 +174,10 @@ var (
 
 	// DefaultStyle is the default style to use for drawing widgets
 	DefaultStyle = Style{
+		AxisXColor:           ColorIToV(179, 64, 64, 255),
+		AxisYColor:           ColorIToV(64, 153, 64, 255),
+		AxisZColor:           ColorIToV(64, 89, 179, 255),
+		AxisWColor:           ColorIToV(102, 102, 102, 255),
 		ButtonColor:          ColorIToV(171, 102, 102, 153),
 		ButtonActiveColor:    ColorIToV(204, 128, 120, 255),
 		ButtonHoverColor:     ColorIToV(171, 102, 102, 255),
@@ -121,6 +189,24 @@ var (
 		CheckboxCursorWidth:  15.0,
 		CheckboxMargin:       mgl.Vec4{2, 2, 2, 2},
 		CheckboxPadding:      mgl.Vec4{4, 4, 4, 4},
+		ChildBgColor:         ColorIToV(0, 0, 0, 51),
+		ChildBorderColor:     ColorIToV(128, 128, 128, 128),
+		ChildBorderWidth:     1.0,
+		ChildMargin:          mgl.Vec4{2, 2, 2, 2},
+		ColorPickerSize:      150.0,
+		ColorPickerBarWidth:  20.0,
+		ComboBgColor:         ColorIToV(77, 77, 102, 242),
+		ComboHoverColor:      ColorIToV(102, 102, 204, 255),
+		ComboTextColor:       ColorIToV(230, 230, 230, 255),
+		ComboMargin:          mgl.Vec4{2, 2, 2, 2},
+		ComboPadding:         mgl.Vec4{4, 4, 4, 4},
+		ComboMaxItems:        8,
+		DockActiveColor:      ColorIToV(102, 102, 204, 255),
+		DockPreviewColor:     ColorIToV(102, 102, 204, 77),
+		DockSplitterColor:    ColorIToV(51, 51, 64, 255),
+		DockTargetColor:      ColorIToV(179, 179, 204, 204),
+		DockSplitterSize:     4.0,
+		DockTargetSize:       24.0,
 		EditboxBgColor:       ColorIToV(128, 128, 128, 179),
 		EditboxActiveColor:   ColorIToV(204, 128, 120, 255),
 		EditboxCursorColor:   ColorIToV(230, 230, 230, 255),
@@ -128,11 +214,28 @@ var (
 		EditboxBlinkDuration: 0.25,
 		EditboxBlinkInterval: 1.0,
 		EditboxTextColor:     ColorIToV(230, 230, 230, 255),
+		EditboxHintColor:     ColorIToV(150, 150, 150, 255),
+		EditboxSelectedColor: ColorIToV(102, 102, 204, 179),
 		EditboxMargin:        mgl.Vec4{2, 2, 2, 2},
 		EditboxPadding:       mgl.Vec4{2, 2, 4, 4},
+		FocusColor:           ColorIToV(230, 200, 90, 255),
+		FocusBorderWidth:     2.0,
 		FontName:             "Default",
 		ImageMargin:          mgl.Vec4{0, 0, 0, 0},
 		IndentSpacing:        26.0,
+		MenuBarBgColor:       ColorIToV(51, 51, 64, 255),
+		MenuDisabledColor:    ColorIToV(128, 128, 128, 255),
+		MenuHoverColor:       ColorIToV(102, 102, 204, 255),
+		MenuShortcutColor:    ColorIToV(179, 179, 179, 255),
+		MenuTextColor:        ColorIToV(230, 230, 230, 255),
+		MenuPadding:          mgl.Vec4{8, 8, 4, 4},
+		PopupBgColor:         ColorIToV(38, 38, 51, 242),
+		PopupDimColor:        ColorIToV(0, 0, 0, 128),
+		PopupWidth:           200.0,
+		ResizeBorderWidth:    4.0,
+		ResizeGripColor:      ColorIToV(102, 102, 204, 128),
+		ResizeGripHoverColor: ColorIToV(102, 102, 204, 255),
+		ResizeGripSize:       12.0,
 		ScrollBarCursorColor: ColorIToV(102, 102, 204, 77),
 		ScrollBarBgColor:     ColorIToV(51, 64, 77, 153),
 		ScrollBarWidth:       16.0,
@@ -148,6 +251,19 @@ var (
 		SliderPadding:        mgl.Vec4{2, 2, 4, 4},
 		SliderTextColor:      ColorIToV(230, 230, 230, 255),
 		SliderCursorWidth:    15.0,
+		TabActiveColor:       ColorIToV(102, 102, 204, 255),
+		TabColor:             ColorIToV(77, 77, 102, 204),
+		TabHoverColor:        ColorIToV(102, 102, 179, 255),
+		TabTextColor:         ColorIToV(230, 230, 230, 255),
+		TabMargin:            mgl.Vec4{2, 2, 2, 2},
+		TabPadding:           mgl.Vec4{8, 8, 4, 4},
+		TabSpacing:           2.0,
+		TableBorderColor:     ColorIToV(128, 128, 128, 128),
+		TableBorderWidth:     1.0,
+		TableHeaderColor:     ColorIToV(77, 77, 102, 204),
+		TableHoverColor:      ColorIToV(102, 102, 179, 255),
+		TableRowColor:        ColorIToV(0, 0, 0, 0),
+		TableRowAltColor:     ColorIToV(255, 255, 255, 15),
 		TextMargin:           mgl.Vec4{4, 4, 6, 6},
 		TextColor:            ColorIToV(230, 230, 230, 255),
 		TitleBarPadding:      mgl.Vec4{2, 2, 6, 6},
@@ -177,3 +293,75 @@ func ClipF32(min, max, value float32) float32 {
 	}
 	return value
 }
+
+// RGBToHSV converts the red, green and blue color components in the range of
+// [0 .. 1] to hue, saturation and value components in the range of [0 .. 1].
+func RGBToHSV(r, g, b float32) (float32, float32, float32) {
+	max := r
+	if g > max {
+		max = g
+	}
+	if b > max {
+		max = b
+	}
+	min := r
+	if g < min {
+		min = g
+	}
+	if b < min {
+		min = b
+	}
+
+	v := max
+	delta := max - min
+	if max <= 0.0 || delta <= 0.0 {
+		return 0.0, 0.0, v
+	}
+	s := delta / max
+
+	var h float32
+	switch max {
+	case r:
+		h = (g - b) / delta
+	case g:
+		h = 2.0 + (b-r)/delta
+	default:
+		h = 4.0 + (r-g)/delta
+	}
+	h /= 6.0
+	if h < 0.0 {
+		h += 1.0
+	}
+
+	return h, s, v
+}
+
+// HSVToRGB converts the hue, saturation and value color components in the
+// range of [0 .. 1] to red, green and blue components in the range of [0 .. 1].
+func HSVToRGB(h, s, v float32) (float32, float32, float32) {
+	if s <= 0.0 {
+		return v, v, v
+	}
+
+	h = (h - float32(math.Floor(float64(h)))) * 6.0
+	sector := int(h)
+	f := h - float32(sector)
+	p := v * (1.0 - s)
+	q := v * (1.0 - s*f)
+	t := v * (1.0 - s*(1.0-f))
+
+	switch sector {
+	case 0:
+		return v, t, p
+	case 1:
+		return q, v, p
+	case 2:
+		return p, v, t
+	case 3:
+		return p, q, v
+	case 4:
+		return t, p, v
+	default:
+		return v, p, q
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+// SetFocusedID gives keyboard focus to the widget with the given id. If a
+// different widget is the active text editor, it loses the editing focus.
+func (ui *Manager) SetFocusedID(id string) {
+	ui.focusedID = id
+	ui.focusVisible = true
+	if ui.activeTextEdit != nil && ui.activeTextEdit.ID != id {
+		ui.clearActiveTextEditor()
+	}
+}
+
+// GetFocusedID returns the id of the widget with keyboard focus or an empty
+// string if no widget has focus.
+func (ui *Manager) GetFocusedID() string {
+	return ui.focusedID
+}
+
+// ClearFocusedID removes keyboard focus from all widgets.
+func (ui *Manager) ClearFocusedID() {
+	ui.focusedID = ""
+	ui.focusVisible = false
+}
+
+// setFocusFromMouse gives keyboard focus to the widget that was clicked
+// without highlighting it.
+func (ui *Manager) setFocusFromMouse(id string) {
+	ui.focusedID = id
+	ui.focusVisible = false
+	ui.focusMoved = false
+}
+
+// moveFocus moves the keyboard focus forwards (direction > 0) or backwards
+// (direction < 0) through the focusable widgets in the order they were built
+// in the last frame, wrapping around at the ends.
+func (ui *Manager) moveFocus(direction int) {
+	order := ui.lastFocusOrder
+	if len(order) == 0 {
+		return
+	}
+
+	current := -1
+	for i, id := range order {
+		if id == ui.focusedID {
+			current = i
+			break
+		}
+	}
+
+	var next int
+	if current < 0 {
+		if direction < 0 {
+			next = len(order) - 1
+		}
+	} else {
+		next = (current + direction + len(order)) % len(order)
+	}
+
+	ui.SetFocusedID(order[next])
+	ui.focusMoved = true
+}
+
+// processNavigationKeys is called at the start of each frame. If no text
+// editor is consuming key events, they are pulled here so that Tab can move
+// the focus and the remaining events can be consumed by the focused widget.
+func (ui *Manager) processNavigationKeys() {
+	ui.navKeyEvents = ui.navKeyEvents[:0]
+
+	// the active text editor takes care of its own key events
+	if ui.activeTextEdit != nil || ui.GetKeyEvents == nil {
+		return
+	}
+
+	for _, event := range ui.GetKeyEvents() {
+		if event.IsRune == false && event.KeyCode == EweyKeyTab {
+			if event.ShiftDown {
+				ui.moveFocus(-1)
+			} else {
+				ui.moveFocus(1)
+			}
+			continue
+		}
+		ui.navKeyEvents = append(ui.navKeyEvents, event)
+	}
+}
+
+// registerFocusable adds the widget id to the focus order for this frame and
+// returns true if the widget currently has keyboard focus. The second result
+// is true the first time the widget is built after keyboard navigation moved
+// the focus to it.
+func (ui *Manager) registerFocusable(id string) (bool, bool) {
+	// widgets under a modal popup can't get the focus
+	if ui.focusBlocked {
+		return false, false
+	}
+
+	ui.focusOrder = append(ui.focusOrder, id)
+	if ui.focusedID == "" || ui.focusedID != id {
+		return false, false
+	}
+
+	arrived := ui.focusMoved
+	ui.focusMoved = false
+	return true, arrived
+}
+
+// consumeActivation returns true if Enter or Space was pressed for the focused
+// widget this frame and removes those events.
+func (ui *Manager) consumeActivation() bool {
+	activated := false
+	remaining := ui.navKeyEvents[:0]
+	for _, event := range ui.navKeyEvents {
+		if (event.IsRune == false && event.KeyCode == EweyKeyEnter) || (event.IsRune && event.Rune == ' ') {
+			activated = true
+			continue
+		}
+		remaining = append(remaining, event)
+	}
+	ui.navKeyEvents = remaining
+	return activated
+}
+
+// consumeKey returns true if the non-rune key was pressed for the focused
+// widget this frame and removes those events.
+func (ui *Manager) consumeKey(keyCode int) bool {
+	pressed := false
+	ui.consumeKeyEvents(func(event KeyPressEvent) bool {
+		if event.IsRune == false && event.KeyCode == keyCode {
+			pressed = true
+			return true
+		}
+		return false
+	})
+	return pressed
+}
+
+// consumeKeyEvents calls the handler for each key event left for the focused
+// widget this frame in order and removes the events it returns true for.
+func (ui *Manager) consumeKeyEvents(handler func(event KeyPressEvent) bool) {
+	remaining := ui.navKeyEvents[:0]
+	for _, event := range ui.navKeyEvents {
+		if !handler(event) {
+			remaining = append(remaining, event)
+		}
+	}
+	ui.navKeyEvents = remaining
+}
+
+// consumeNudge returns the number of steps the arrow keys moved the value
+// of the focused widget this frame and removes those events. Right and Up
+// increase the value while Left and Down decrease it; holding shift makes
+// each press worth ten steps.
+func (ui *Manager) consumeNudge() int {
+	steps := 0
+	remaining := ui.navKeyEvents[:0]
+	for _, event := range ui.navKeyEvents {
+		if event.IsRune == false {
+			amount := 1
+			if event.ShiftDown {
+				amount = 10
+			}
+
+			switch event.KeyCode {
+			case EweyKeyRight, EweyKeyUp:
+				steps += amount
+				continue
+			case EweyKeyLeft, EweyKeyDown:
+				steps -= amount
+				continue
+			}
+		}
+		remaining = append(remaining, event)
+	}
+	ui.navKeyEvents = remaining
+	return steps
+}
+
+// focusBehavior registers the widget as focusable and returns two values:
+// whether or not the widget has keyboard focus and whether or not it was
+// activated with the keyboard this frame.
+func (wnd *Window) focusBehavior(id string) (bool, bool) {
+	focused, _ := wnd.Owner.registerFocusable(id)
+	if !focused {
+		return false, false
+	}
+	return true, wnd.Owner.consumeActivation()
+}
+
+// focusNudge registers the widget as focusable and returns the number of
+// steps the arrow keys moved its value if it has keyboard focus.
+func (wnd *Window) focusNudge(id string) int {
+	focused, _ := wnd.Owner.registerFocusable(id)
+	if !focused {
+		return 0
+	}
+	return wnd.Owner.consumeNudge()
+}
+
+// drawFocusHighlight draws an outline around the widget area if the widget
+// has keyboard focus and the focus was set by keyboard navigation.
+func (wnd *Window) drawFocusHighlight(cmd *cmdList, id string, x, y, w, h float32) {
+	if !wnd.Owner.focusVisible || wnd.Owner.focusedID != id {
+		return
+	}
+
+	combos, indexes, fc := cmd.DrawRectOutlineDC(x, y, x+w, y-h, wnd.Style.FocusBorderWidth, wnd.Style.FocusColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+}
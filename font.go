@@ -235,7 +235,7 @@ func (f *Font) GetRenderSize(msg string) (float32, float32, float32) {
 }
 
 // OffsetFloor returns the maximum width offset that will fit between characters that
-// is still smaller than the offset passed in.
+// is still smaller than the offset passed in. Characters are measured per rune.
 func (f *Font) OffsetFloor(msg string, offset float32) float32 {
 	var w float32
 
@@ -260,13 +260,14 @@ func (f *Font) OffsetFloor(msg string, offset float32) float32 {
 }
 
 // OffsetForIndex returns the width offset that will fit just before the `stopIndex`
-// number character in the msg.
+// number character in the msg. The index counts runes, not bytes.
 func (f *Font) OffsetForIndex(msg string, stopIndex int) float32 {
 	return f.OffsetForIndexAdv(msg, 0, stopIndex)
 }
 
 // OffsetForIndexAdv returns the width offset that will fit just before the `stopIndex`
-// number character in the msg, starting at charStartIndex.
+// number character in the msg, starting at charStartIndex. Both indexes count
+// runes, not bytes.
 func (f *Font) OffsetForIndexAdv(msg string, charStartIndex int, stopIndex int) float32 {
 	var w float32
 
@@ -280,18 +281,61 @@ func (f *Font) OffsetForIndexAdv(msg string, charStartIndex int, stopIndex int)
 
 	// see how much to scale the size based on current resolution vs desgin resolution
 	fontScale := f.GetCurrentScale()
-	for i, ch := range msg[charStartIndex:] {
+	runeIndex := 0
+	for _, ch := range msg {
+		// skip the runes before the start index
+		if runeIndex < charStartIndex {
+			runeIndex++
+			continue
+		}
+
 		// calculate up to the stopIndex but do not include it
-		if i+charStartIndex >= stopIndex {
+		if runeIndex >= stopIndex {
 			break
 		}
 		adv, _ := f.face.GlyphAdvance(ch)
 		w += fixedInt26ToFloat(adv)
+		runeIndex++
 	}
 
 	return w * fontScale
 }
 
+// IndexForOffset returns the index of the rune in msg whose leading edge is
+// closest to the width offset passed in.
+func (f *Font) IndexForOffset(msg string, offset float32) int {
+	return f.IndexForOffsetAdv(msg, 0, offset)
+}
+
+// IndexForOffsetAdv returns the index of the rune in msg whose leading edge is
+// closest to the width offset passed in, measuring from charStartIndex.
+// The index returned counts runes from the start of msg.
+func (f *Font) IndexForOffsetAdv(msg string, charStartIndex int, offset float32) int {
+	var w float32
+
+	// see how much to scale the size based on current resolution vs desgin resolution
+	fontScale := f.GetCurrentScale()
+	runeIndex := 0
+	for _, ch := range msg {
+		// skip the runes before the start index
+		if runeIndex < charStartIndex {
+			runeIndex++
+			continue
+		}
+
+		// stop if the offset is closer to the leading edge of this rune
+		adv, _ := f.face.GlyphAdvance(ch)
+		advf := fixedInt26ToFloat(adv) * fontScale
+		if offset < w+advf*0.5 {
+			return runeIndex
+		}
+		w += advf
+		runeIndex++
+	}
+
+	return runeIndex
+}
+
 // fixedInt26ToFloat converts a fixed int 26:6 precision to a float32.
 func fixedInt26ToFloat(fixedInt fixed.Int26_6) float32 {
 	var result float32
@@ -322,6 +366,7 @@ func (f *Font) CreateText(pos mgl.Vec3, color mgl.Vec4, msg string) TextRenderDa
 // CreateTextAdv makes a new renderable object from the supplied string
 // using the data in the font. The string returned will be the maximum amount of the msg that fits
 // the specified maxWidth (if greater than 0.0) starting at the charOffset specified.
+// Both charOffset and cursorPosition count runes, not bytes.
 // The data is returned as a TextRenderData object.
 func (f *Font) CreateTextAdv(pos mgl.Vec3, color mgl.Vec4, maxWidth float32, charOffset int, cursorPosition int, msg string) TextRenderData {
 	// this is the texture ID of the font to use in the shader; by default
@@ -329,8 +374,9 @@ func (f *Font) CreateTextAdv(pos mgl.Vec3, color mgl.Vec4, maxWidth float32, cha
 	const floatTexturePosition = 0.0
 
 	// sanity checks
-	originalLen := len(msg)
-	trimmedMsg := msg
+	msgRunes := []rune(msg)
+	originalLen := len(msgRunes)
+	trimmedMsg := msgRunes
 	if originalLen == 0 {
 		return TextRenderData{
 			ComboBuffer:         nil,
@@ -347,7 +393,7 @@ func (f *Font) CreateTextAdv(pos mgl.Vec3, color mgl.Vec4, maxWidth float32, cha
 		trimmedMsg = trimmedMsg[charOffset:]
 	}
 
-	// get the length of our message
+	// get the length of our message in runes
 	msgLength := len(trimmedMsg)
 
 	// create the arrays to hold the data to buffer to OpenGL
@@ -355,7 +401,7 @@ func (f *Font) CreateTextAdv(pos mgl.Vec3, color mgl.Vec4, maxWidth float32, cha
 	indexBuffer := make([]uint32, 0, msgLength*6)          // two faces * three indexes
 
 	// do a preliminary test to see how much room the message will take up
-	dimX, dimY, advH := f.GetRenderSize(trimmedMsg)
+	dimX, dimY, advH := f.GetRenderSize(string(trimmedMsg))
 
 	// see how much to scale the size based on current resolution vs desgin resolution
 	fontScale := f.GetCurrentScale()
@@ -366,7 +412,7 @@ func (f *Font) CreateTextAdv(pos mgl.Vec3, color mgl.Vec4, maxWidth float32, cha
 	var cursorOverflowRight bool
 	var penX = pos[0]
 	var penY = pos[1] - float32(advH)
-	for chi, ch := range trimmedMsg {
+	for _, ch := range trimmedMsg {
 		// get the rune data
 		chData := f.locations[ch]
 
@@ -393,7 +439,7 @@ func (f *Font) CreateTextAdv(pos mgl.Vec3, color mgl.Vec4, maxWidth float32, cha
 			// we overflowed the size of the string, now check to see if
 			// the cursor position is covered within this string or if that hasn't
 			// been reached yet.
-			if cursorPosition >= 0 && cursorPosition-charOffset > chi {
+			if cursorPosition >= 0 && cursorPosition-charOffset > totalChars {
 				cursorOverflowRight = true
 			}
 
@@ -442,7 +488,7 @@ func (f *Font) CreateTextAdv(pos mgl.Vec3, color mgl.Vec4, maxWidth float32, cha
 		comboBuffer = append(comboBuffer, floatTexturePosition)
 		comboBuffer = append(comboBuffer, color[:]...)
 
-		startIndex := uint32(chi) * 4
+		startIndex := uint32(totalChars) * 4
 		indexBuffer = append(indexBuffer, startIndex)
 		indexBuffer = append(indexBuffer, startIndex+1)
 		indexBuffer = append(indexBuffer, startIndex+2)
@@ -116,8 +116,10 @@ func SetInputHandlers(uiman *gui.Manager, window *glfw.Window) {
 			}
 		} else {
 			if action == gui.MouseDown {
-				// check to see if there was a transition from UP to DOWN
-				if mbData.lastAction == gui.MouseUp {
+				// check to see if there was a transition from UP to DOWN; the
+				// last action could also be a click if the button got pressed
+				// again right after being released.
+				if mbData.lastAction != gui.MouseDown {
 					// check to see the time between the last UP->DOWN transition
 					// and this one. If it's less than the double click threshold
 					// then change the doubleClickDetected member so that the
@@ -268,6 +270,17 @@ func SetInputHandlers(uiman *gui.Manager, window *glfw.Window) {
 
 	//keyTranslation[glfw.Key] = gui.EweyKey
 
+	// these are the keys that get passed through as runes when ctrl is held
+	// down so that editors can implement clipboard and selection commands.
+	ctrlRunes := map[glfw.Key]rune{
+		glfw.KeyA: 'A',
+		glfw.KeyC: 'C',
+		glfw.KeyV: 'V',
+		glfw.KeyX: 'X',
+		glfw.KeyY: 'Y',
+		glfw.KeyZ: 'Z',
+	}
+
 	// create our own handler for key input so that it can buffer the keys
 	// and then consume them in an edit box or whatever widget has focus.
 	var prevKeyCallback glfw.KeyCallback
@@ -289,10 +302,14 @@ func SetInputHandlers(uiman *gui.Manager, window *glfw.Window) {
 			// when ctrl is held down, it doesn't appear that runes get sent
 			// through the CharModsCallback function, so we must handle the
 			// ones we want here.
-			if (key == glfw.KeyV) && (mods&glfw.ModControl == glfw.ModControl) {
-				kpe.Rune = 'V'
+			ctrlRune, isCtrlRune := ctrlRunes[key]
+			if isCtrlRune && (mods&glfw.ModControl == glfw.ModControl) {
+				kpe.Rune = ctrlRune
 				kpe.IsRune = true
 				kpe.CtrlDown = true
+				if mods&glfw.ModShift == glfw.ModShift {
+					kpe.ShiftDown = true
+				}
 			} else {
 				return
 			}
@@ -364,6 +381,15 @@ func SetInputHandlers(uiman *gui.Manager, window *glfw.Window) {
 		keyBuffer = keyBuffer[:0]
 	}
 
+	uiman.IsKeyDown = func(keyCode int) bool {
+		for glfwKey, eweyKey := range keyTranslation {
+			if eweyKey == keyCode {
+				return window.GetKey(glfwKey) == glfw.Press
+			}
+		}
+		return false
+	}
+
 	uiman.GetClipboardString = func() (string, error) {
 		return window.GetClipboardString()
 	}
@@ -371,4 +397,16 @@ func SetInputHandlers(uiman *gui.Manager, window *glfw.Window) {
 	uiman.SetClipboardString = func(clippy string) {
 		window.SetClipboardString(clippy)
 	}
+
+	// GLFW 3.1 has no diagonal resize cursors, so the crosshair is used for those.
+	cursors := map[int]*glfw.Cursor{
+		gui.CursorArrow:      glfw.CreateStandardCursor(glfw.ArrowCursor),
+		gui.CursorResizeEW:   glfw.CreateStandardCursor(glfw.HResizeCursor),
+		gui.CursorResizeNS:   glfw.CreateStandardCursor(glfw.VResizeCursor),
+		gui.CursorResizeNWSE: glfw.CreateStandardCursor(glfw.CrosshairCursor),
+		gui.CursorResizeNESW: glfw.CreateStandardCursor(glfw.CrosshairCursor),
+	}
+	uiman.SetCursorShape = func(shape int) {
+		window.SetCursor(cursors[shape])
+	}
 }
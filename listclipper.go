@@ -0,0 +1,110 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// ListClipper tells which items of a long list of rows with the same height
+// are in the visible area of the window so that only those need to be built.
+// The rows that are skipped still move the widget cursor so that the scroll
+// bar and the widgets after the list are placed as if every row was built.
+type ListClipper struct {
+	// DisplayStart is the index of the first visible item.
+	DisplayStart int
+
+	// DisplayEnd is one past the index of the last visible item.
+	DisplayEnd int
+
+	wnd          *Window
+	table        *tableState
+	itemCount    int
+	itemHeightDC float32
+}
+
+// BeginListClipper starts a list of itemCount rows that are each itemHeightS
+// tall in screen-normalized space at the start of the next row and returns a
+// ListClipper with the range of the items that are visible. Build the rows
+// from DisplayStart up to DisplayEnd, each starting with StartRow(), and then
+// call End(). Inside of a table, build the rows with TableNextRow() instead.
+// Rows that are taller or shorter than itemHeightS throw off the placement
+// of the rows after them and only the built rows count towards the width of
+// the window's content.
+func (wnd *Window) BeginListClipper(itemCount int, itemHeightS float32) *ListClipper {
+	c := new(ListClipper)
+	c.wnd = wnd
+	c.itemCount = itemCount
+	_, c.itemHeightDC = wnd.Owner.ScreenToDisplay(0.0, itemHeightS)
+
+	// the top of the list in display coordinates; in a table the rows are
+	// placed by the table instead of the widget cursor
+	var startY float32
+	if t := wnd.currentTable(); t != nil && !t.cellOpen {
+		c.table = t
+		wnd.endTableRow(t)
+		startY = t.rowTop
+	} else {
+		wnd.StartRow()
+		startY = wnd.getCursorDC()[1]
+	}
+
+	// find the items that overlap the visible area
+	c.DisplayEnd = itemCount
+	if c.itemHeightDC > 0.0 {
+		visible := wnd.visibleRectDC()
+		c.DisplayStart = int((startY - visible[1]) / c.itemHeightDC)
+		c.DisplayEnd = int(math.Ceil(float64((startY - visible[1] + visible[3]) / c.itemHeightDC)))
+	}
+	if c.DisplayStart < 0 {
+		c.DisplayStart = 0
+	}
+	if c.DisplayStart > itemCount {
+		c.DisplayStart = itemCount
+	}
+	if c.DisplayEnd > itemCount {
+		c.DisplayEnd = itemCount
+	}
+	if c.DisplayEnd < c.DisplayStart {
+		c.DisplayEnd = c.DisplayStart
+	}
+
+	// skip the rows above the visible area
+	c.skip(c.DisplayStart)
+	return c
+}
+
+// End skips the rows of the list below the visible area.
+func (c *ListClipper) End() {
+	if c.table != nil {
+		c.wnd.endTableRow(c.table)
+	} else {
+		c.wnd.StartRow()
+	}
+	c.skip(c.itemCount - c.DisplayEnd)
+}
+
+// skip moves the widget cursor or the rows of the table down past count rows.
+func (c *ListClipper) skip(count int) {
+	heightDC := float32(count) * c.itemHeightDC
+	if c.table != nil {
+		c.table.rowTop -= heightDC
+		c.table.rowIndex += count
+	} else {
+		c.wnd.widgetCursorDC[1] -= heightDC
+	}
+}
+
+// visibleRectDC returns the area widgets can be seen in as [x,y,w,h] in
+// display coordinates, which is the current clip rectangle or the window
+// below its title bar and menu bar.
+func (wnd *Window) visibleRectDC() mgl.Vec4 {
+	if len(wnd.clipStack) > 0 {
+		return wnd.currentClipRect()
+	}
+	x, y, w, h := wnd.windowDisplaySize()
+	return mgl.Vec4{x, y - wnd.titleBarHeight() - wnd.menuBarHeight(), w, h}
+}
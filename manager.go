@@ -29,8 +29,40 @@ type textEditState struct {
 	// [0 .. Style.EditboxBlinkDuration].
 	CursorTimer float32
 
-	// CharacterShift is the amount of characters to shift the dispayed text.
+	// CharacterShift is the amount of runes to shift the dispayed text.
 	CharacterShift int
+
+	// SelectionAnchor is the rune offset where the selection started; the
+	// selection is the range between it and CursorOffset.
+	SelectionAnchor int
+
+	// EditBuffer is the text being edited for widgets that don't edit a
+	// string value directly, such as the numeric input widgets.
+	EditBuffer string
+
+	// Options are the input restrictions of the editing widget.
+	Options EditboxOptions
+
+	// UndoStack is the history of edits that can be undone, oldest first.
+	UndoStack []textEditSnapshot
+
+	// RedoStack is the history of undone edits that can be redone.
+	RedoStack []textEditSnapshot
+
+	// CoalesceTyping is true if the last edit was a typed rune so that the
+	// next typed rune can be undone together with it.
+	CoalesceTyping bool
+
+	// Alive is set when the widget is built so that the state of widgets
+	// that are no longer built can be discarded.
+	Alive bool
+}
+
+// textEditSnapshot is the text and cursor state of an editor in the undo history.
+type textEditSnapshot struct {
+	Text            string
+	CursorOffset    int
+	SelectionAnchor int
 }
 
 // Manager holds all of the widgets and knows how to draw the UI.
@@ -66,6 +98,11 @@ type Manager struct {
 	// ClearKeyEvents is the function to be called to clear out the key press event buffer
 	ClearKeyEvents func()
 
+	// IsKeyDown, if set, should be a function that returns true if the key,
+	// one of the EweyKey* constants, is currently held down. It's used to
+	// scroll windows sideways with the mouse wheel while shift is held.
+	IsKeyDown func(keyCode int) bool
+
 	// GetClipboardString returns a possible string from the clipboarnd and
 	// possibly an error.
 	GetClipboardString func() (string, error)
@@ -73,6 +110,11 @@ type Manager struct {
 	// SetClipboardString sets a string in the system clipboard.
 	SetClipboardString func(string)
 
+	// SetCursorShape, if set, gets called with one of the Cursor* constants
+	// when the user interface wants the mouse cursor to change shape, such as
+	// while the mouse is over an edge of a window that can be resized.
+	SetCursorShape func(shape int)
+
 	// FrameStart is the time the UI manager's Construct() was called.
 	FrameStart time.Time
 
@@ -82,6 +124,18 @@ type Manager struct {
 	// ScrollSpeed is how much each move of the scroll wheel should be magnified
 	ScrollSpeed float32
 
+	// UndoDepth is the maximum number of edits each text editing widget can undo.
+	UndoDepth int
+
+	// MainMenuBar, if set, gets called each frame to build the menus of the
+	// main menu bar, which is drawn across the top of the screen above all of
+	// the windows, with calls to Window.Menu().
+	MainMenuBar BuildCallback
+
+	// DockStyle is the style for the splitter bars between docked windows and
+	// the drop targets shown while dragging a window to dock it.
+	DockStyle Style
+
 	// width is used to construct the ortho projection matrix and is probably
 	// best set to the width of the window.
 	width int32
@@ -97,9 +151,14 @@ type Manager struct {
 	// to 1600x1200 will instruct the package to create text with a height of 60.
 	designHeight int32
 
-	// windows is the slice of known windows to render.
+	// windows is the slice of known windows to render in drawing order from
+	// the bottom up.
 	windows []*Window
 
+	// focusedWindow is the window that was clicked last or nil if the last
+	// click wasn't in a window.
+	focusedWindow *Window
+
 	// activeInputID is the ID string of the widget that claimed input on mouse down.
 	activeInputID string
 
@@ -107,6 +166,114 @@ type Manager struct {
 	// then there are no text editing widgets with active input focus.
 	activeTextEdit *textEditState
 
+	// textEditStates are the states of the text editing widgets that have been
+	// edited, keyed by ID, which keeps their undo history while they are built.
+	textEditStates map[string]*textEditState
+
+	// focusedID is the ID string of the widget that has keyboard focus.
+	focusedID string
+
+	// focusVisible is true if the keyboard focus was set by navigation and
+	// should be highlighted; clicking a widget focuses it without a highlight.
+	focusVisible bool
+
+	// focusMoved is true if keyboard navigation moved the focus and the
+	// newly focused widget hasn't been built yet.
+	focusMoved bool
+
+	// focusOrder is the slice of focusable widget IDs in the order they were
+	// built during the current frame.
+	focusOrder []string
+
+	// lastFocusOrder is the focusOrder of the last completed frame which is
+	// used to move the focus with Tab and Shift+Tab.
+	lastFocusOrder []string
+
+	// wheelCaptured is set by widgets that scroll their own content with the
+	// mouse wheel when the mouse is over them during this frame.
+	wheelCaptured bool
+
+	// wheelCapturedLastFrame is wheelCaptured from the last frame; windows
+	// don't scroll while it is set so that the wheel only scrolls the widget.
+	wheelCapturedLastFrame bool
+
+	// navKeyEvents are the key events pulled at the start of the frame when
+	// no text editor is active; the focused widget consumes them.
+	navKeyEvents []KeyPressEvent
+
+	// mouseOwner is the window that gets mouse input during this frame.
+	mouseOwner *Window
+
+	// lmbWasDown is true if the left mouse button was down last frame.
+	lmbWasDown bool
+
+	// lmbPressed is true if the left mouse button got pressed this frame.
+	lmbPressed bool
+
+	// rmbWasDown is true if the right mouse button was down last frame.
+	rmbWasDown bool
+
+	// rmbPressed is true if the right mouse button got pressed this frame.
+	rmbPressed bool
+
+	// overlayCmds are the command lists drawn on top of all of the windows
+	// during this frame, such as the list of an open combo box.
+	overlayCmds []*cmdList
+
+	// overlayRects are the areas covered by the overlay during this frame as
+	// [x,y,w,h] in display coordinates.
+	overlayRects []mgl.Vec4
+
+	// lastOverlayRects are the overlayRects of the last frame; windows don't
+	// get mouse input while the mouse is inside of one.
+	lastOverlayRects []mgl.Vec4
+
+	// openCombo is the state of the open combo box or nil if none are open.
+	openCombo *comboState
+
+	// mainMenuBar is the window holding the main menu bar or nil if there is
+	// no MainMenuBar callback.
+	mainMenuBar *Window
+
+	// popups is the stack of open popups in the order they were opened; they
+	// are built after the windows and drawn on top of them.
+	popups []*popupState
+
+	// buildingPopup is the popup being built or nil if none are.
+	buildingPopup *popupState
+
+	// cursorShape is the Cursor* constant for the shape the mouse cursor
+	// should have this frame.
+	cursorShape int
+
+	// lastCursorShape is the cursor shape last passed to SetCursorShape.
+	lastCursorShape int
+
+	// dockRoots are the root nodes of the trees of docked windows.
+	dockRoots []*DockNode
+
+	// dockNodeCount is the number of dock nodes created, which is used to
+	// give them unique IDs.
+	dockNodeCount int
+
+	// dockDragWindow is the window being dragged by its title bar, which
+	// can be docked by releasing it on a drop target, or nil if none is.
+	dockDragWindow *Window
+
+	// dockCmds are the command lists for the splitter bars between docked
+	// windows, which are drawn under all of the windows.
+	dockCmds []*cmdList
+
+	// focusBlocked is set while the widgets under a modal popup are built so
+	// that they can't get keyboard focus.
+	focusBlocked bool
+
+	// colorPicker is the state of the color picker popup or nil if it's not open.
+	colorPicker *colorPickerState
+
+	// recentColors are the colors last picked with the color picker, newest first.
+	recentColors []mgl.Vec4
+
 	// gfx is the underlying graphics implementation to be used for rendering.
 	gfx graphics.GraphicsProvider
 
@@ -143,6 +310,9 @@ func NewManager(gfx graphics.GraphicsProvider) *Manager {
 	m.whitePixelUv = mgl.Vec4{1.0, 1.0, 1.0, 1.0}
 	m.FrameStart = time.Now()
 	m.ScrollSpeed = 10.0
+	m.UndoDepth = 100
+	m.DockStyle = DefaultStyle
+	m.textEditStates = make(map[string]*textEditState)
 
 	m.vao = gfx.GenVertexArray()
 
@@ -223,6 +393,7 @@ func (ui *Manager) GetWindow(id string) *Window {
 // GetWindowsByFilter returns a slice of *Window which is populated by
 // filtering the internal window list with the function provided.
 // If the function returns true the window will get included in the results.
+// The windows are in drawing order from the bottom up.
 func (ui *Manager) GetWindowsByFilter(filter func(w *Window) bool) []*Window {
 	results := []*Window{}
 	for _, wnd := range ui.windows {
@@ -236,6 +407,7 @@ func (ui *Manager) GetWindowsByFilter(filter func(w *Window) bool) []*Window {
 
 // RemoveWindow will remove the window from the user interface.
 func (ui *Manager) RemoveWindow(wndToRemove *Window) {
+	ui.UndockWindow(wndToRemove)
 	filtered := ui.windows[:0]
 	for _, wnd := range ui.windows {
 		if wnd.ID != wndToRemove.ID {
@@ -243,6 +415,129 @@ func (ui *Manager) RemoveWindow(wndToRemove *Window) {
 		}
 	}
 	ui.windows = filtered
+	if ui.focusedWindow == wndToRemove {
+		ui.focusedWindow = nil
+	}
+}
+
+// these constants are the layers windows are kept in from the bottom up.
+const (
+	windowLayerBottom = iota
+	windowLayerNormal
+	windowLayerTop
+)
+
+// layer returns the windowLayer* constant for the layer the window is kept in.
+// Windows docked at the edges of the screen stay under the floating windows.
+func (wnd *Window) layer() int {
+	if wnd.AlwaysOnTop {
+		return windowLayerTop
+	}
+	if root := wnd.dockRoot(); wnd.AlwaysOnBottom || (root != nil && root.fillsScreen) {
+		return windowLayerBottom
+	}
+	return windowLayerNormal
+}
+
+// sortWindows orders the windows by layer without changing the order of the
+// windows within a layer.
+func (ui *Manager) sortWindows() {
+	sorted := make([]*Window, 0, len(ui.windows))
+	for layer := windowLayerBottom; layer <= windowLayerTop; layer++ {
+		for _, wnd := range ui.windows {
+			if wnd.layer() == layer {
+				sorted = append(sorted, wnd)
+			}
+		}
+	}
+	ui.windows = sorted
+}
+
+// windowGroup returns the windows that move in the drawing order together with
+// the window, in their current order. These are all of the windows docked in
+// the same tree so that floating windows don't end up between them.
+func (ui *Manager) windowGroup(wnd *Window) []*Window {
+	root := wnd.dockRoot()
+	if root == nil {
+		return []*Window{wnd}
+	}
+	group := []*Window{}
+	for _, w := range ui.windows {
+		if w.dockRoot() == root {
+			group = append(group, w)
+		}
+	}
+	return group
+}
+
+// SetWindowOrder moves the window to the index in the drawing order, where 0
+// is the bottom and windows with a higher index are drawn on top of it. Windows
+// docked with it move along and AlwaysOnTop and AlwaysOnBottom still keep
+// windows above or below the others.
+func (ui *Manager) SetWindowOrder(wnd *Window, index int) {
+	group := ui.windowGroup(wnd)
+	rest := make([]*Window, 0, len(ui.windows))
+	for _, w := range ui.windows {
+		inGroup := false
+		for _, gw := range group {
+			if w == gw {
+				inGroup = true
+				break
+			}
+		}
+		if !inGroup {
+			rest = append(rest, w)
+		}
+	}
+
+	if index < 0 {
+		index = 0
+	} else if index > len(rest) {
+		index = len(rest)
+	}
+
+	windows := make([]*Window, 0, len(ui.windows))
+	windows = append(windows, rest[:index]...)
+	windows = append(windows, group...)
+	windows = append(windows, rest[index:]...)
+	ui.windows = windows
+	ui.sortWindows()
+}
+
+// BringWindowToFront moves the window on top of the other windows in its layer.
+func (ui *Manager) BringWindowToFront(wnd *Window) {
+	ui.SetWindowOrder(wnd, len(ui.windows))
+}
+
+// SendWindowToBack moves the window under the other windows in its layer.
+func (ui *Manager) SendWindowToBack(wnd *Window) {
+	ui.SetWindowOrder(wnd, 0)
+}
+
+// SetFocusedWindow focuses the window and brings it to the front like clicking
+// it does. Passing nil leaves no window focused.
+func (ui *Manager) SetFocusedWindow(wnd *Window) {
+	ui.focusedWindow = wnd
+	if wnd != nil {
+		ui.BringWindowToFront(wnd)
+	}
+}
+
+// GetFocusedWindow returns the window that was clicked last or nil if no
+// window has the focus.
+func (ui *Manager) GetFocusedWindow() *Window {
+	return ui.focusedWindow
+}
+
+// isManagedWindow returns true if the window was created with NewWindow()
+// and not removed since, unlike the windows of popups and the main menu bar.
+func (ui *Manager) isManagedWindow(wnd *Window) bool {
+	for _, w := range ui.windows {
+		if w == wnd {
+			return true
+		}
+	}
+	return false
 }
 
 // NewFont loads the font from a file and 'registers' it with the UI manager.
@@ -318,11 +613,21 @@ func (ui *Manager) setActiveTextEditor(id string, cursorPos int) bool {
 		return false
 	}
 
-	// claim the fresh focus
-	var ate textEditState
-	ate.ID = id
+	// claim the fresh focus, reusing the state from the last time the widget
+	// was edited so that the undo history is kept.
+	ate, found := ui.textEditStates[id]
+	if !found {
+		ate = &textEditState{ID: id}
+		ui.textEditStates[id] = ate
+	}
 	ate.CursorOffset = cursorPos
-	ui.activeTextEdit = &ate
+	ate.SelectionAnchor = cursorPos
+	ate.CursorTimer = 0.0
+	ate.CharacterShift = 0
+	ate.CoalesceTyping = false
+	ate.Options = EditboxOptions{}
+	ate.Alive = true
+	ui.activeTextEdit = ate
 
 	// clear out the old key events
 	ui.ClearKeyEvents()
@@ -335,6 +640,121 @@ func (ui *Manager) getActiveTextEditor() *textEditState {
 	return ui.activeTextEdit
 }
 
+// keepTextEditState marks the state of the text editing widget as alive for
+// this frame if the widget has one.
+func (ui *Manager) keepTextEditState(id string) {
+	if ate, found := ui.textEditStates[id]; found {
+		ate.Alive = true
+	}
+}
+
+// pruneTextEditStates discards the states of text editing widgets that were
+// not built during the last frame.
+func (ui *Manager) pruneTextEditStates() {
+	for id, ate := range ui.textEditStates {
+		if !ate.Alive {
+			delete(ui.textEditStates, id)
+			if ui.activeTextEdit == ate {
+				ui.activeTextEdit = nil
+			}
+			continue
+		}
+		ate.Alive = false
+	}
+}
+
+// windowAt returns the top-most window or popup containing the display
+// coordinate or nil if there is none. Nothing under a modal popup is returned.
+func (ui *Manager) windowAt(x, y float32) *Window {
+	for i := len(ui.popups) - 1; i >= 0; i-- {
+		p := ui.popups[i]
+		if p.Window.ContainsPosition(x, y) {
+			return p.Window
+		}
+		if p.Modal {
+			return nil
+		}
+	}
+	if ui.mainMenuBar != nil && ui.mainMenuBar.ContainsPosition(x, y) {
+		return ui.mainMenuBar
+	}
+	for i := len(ui.windows) - 1; i >= 0; i-- {
+		if ui.windows[i].isShown() && ui.windows[i].ContainsPosition(x, y) {
+			return ui.windows[i]
+		}
+	}
+	return nil
+}
+
+// updateMouseButtons tracks which mouse buttons got pressed this frame.
+func (ui *Manager) updateMouseButtons() {
+	lmbDown := ui.GetMouseButtonAction(0) == MouseDown
+	ui.lmbPressed = lmbDown && !ui.lmbWasDown
+	ui.lmbWasDown = lmbDown
+	rmbDown := ui.GetMouseButtonAction(1) == MouseDown
+	ui.rmbPressed = rmbDown && !ui.rmbWasDown
+	ui.rmbWasDown = rmbDown
+}
+
+// updateMouseOwner picks the window that gets mouse input for this frame. It's
+// the top-most window under the mouse, except while the left mouse button is
+// held down, when the window it was pressed in keeps it so that drags can
+// leave the window.
+func (ui *Manager) updateMouseOwner() {
+	if ui.lmbPressed {
+		mx, my := ui.GetMouseDownPosition(0)
+		ui.mouseOwner = ui.windowAt(mx, my)
+	} else if ui.GetMouseButtonAction(0) != MouseDown {
+		mx, my := ui.GetMousePosition()
+		ui.mouseOwner = ui.windowAt(mx, my)
+	}
+
+	// the overlay is on top of all of the windows
+	if ui.mouseOwner != nil {
+		mx, my := ui.GetMousePosition()
+		if ui.overlayContains(mx, my) {
+			ui.mouseOwner = nil
+		}
+	}
+
+	// pressing a mouse button in a window focuses it and brings it to the
+	// front while pressing it outside of all of them takes the focus away.
+	// popups, the main menu bar and the overlay don't change the focus.
+	if !ui.lmbPressed && !ui.rmbPressed {
+		return
+	}
+	if ui.isManagedWindow(ui.mouseOwner) {
+		ui.SetFocusedWindow(ui.mouseOwner)
+		return
+	}
+	mx, my := ui.GetMousePosition()
+	if ui.mouseOwner == nil && len(ui.popups) == 0 && !ui.overlayContains(mx, my) {
+		ui.focusedWindow = nil
+	}
+}
+
+// overlayContains returns true if the display coordinate is inside of the
+// overlay drawn last frame.
+func (ui *Manager) overlayContains(x, y float32) bool {
+	for _, r := range ui.lastOverlayRects {
+		if x > r[0] && y > r[1]-r[3] && x < r[0]+r[2] && y < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// addOverlayCmd adds a new cmdList to the overlay that is clipped to the
+// rectangle with the top-left corner at (x,y) and the size (w,h), all in
+// display coordinates. The rectangle blocks mouse input to the windows.
+func (ui *Manager) addOverlayCmd(x, y, w, h float32) *cmdList {
+	cmd := newCmdList()
+	cmd.clipRect = mgl.Vec4{x, y, w, h}
+	ui.overlayCmds = append(ui.overlayCmds, cmd)
+	ui.overlayRects = append(ui.overlayRects, cmd.clipRect)
+	return cmd
+}
+
 // clearActiveTextEditor will remove the active text editor from tracking.
 func (ui *Manager) clearActiveTextEditor() {
 	ui.activeTextEdit = nil
@@ -366,10 +786,69 @@ func (ui *Manager) Construct(frameDelta float64) {
 		ui.ClearActiveInputID()
 	}
 
-	// loop through all of the windows and tell them to self-construct.
+	// widgets ask for a different cursor shape while the mouse is over them
+	ui.cursorShape = CursorArrow
+
+	// forget about text editors that are gone
+	ui.pruneTextEditStates()
+
+	// start a new overlay and remember where the last one was
+	ui.overlayCmds = ui.overlayCmds[:0]
+	ui.lastOverlayRects, ui.overlayRects = ui.overlayRects, ui.lastOverlayRects[:0]
+	ui.pruneCombo()
+
+	// keep the windows in their layers and then figure out which window gets
+	// the mouse and update the popups
+	ui.sortWindows()
+	ui.updateMouseButtons()
+	ui.updateMouseOwner()
+	ui.updatePopups()
+
+	// track if a widget used the scroll wheel last frame
+	ui.wheelCapturedLastFrame, ui.wheelCaptured = ui.wheelCaptured, false
+
+	// keep the focus order of the last frame for keyboard navigation and
+	// then handle the navigation keys for this frame.
+	ui.lastFocusOrder, ui.focusOrder = ui.focusOrder, ui.lastFocusOrder[:0]
+	ui.processNavigationKeys()
+
+	// loop through all of the windows and tell them to self-construct and
+	// then build the main menu bar and the popups on top of them.
+	ui.focusBlocked = ui.topModalIndex() >= 0
+	ui.updateDocks()
 	for _, w := range ui.windows {
+		if !w.isShown() {
+			w.cmds = w.cmds[:0]
+			continue
+		}
 		w.construct()
 	}
+	ui.constructMainMenuBar()
+	ui.constructPopups()
+	ui.updateDockDrop()
+
+	// let the client know if the cursor should change shape
+	if ui.cursorShape != ui.lastCursorShape {
+		ui.lastCursorShape = ui.cursorShape
+		if ui.SetCursorShape != nil {
+			ui.SetCursorShape(ui.cursorShape)
+		}
+	}
+}
+
+// isShiftDown returns true if either shift key is held down according to the
+// IsKeyDown function.
+func (ui *Manager) isShiftDown() bool {
+	if ui.IsKeyDown == nil {
+		return false
+	}
+	return ui.IsKeyDown(EweyKeyLeftShift) || ui.IsKeyDown(EweyKeyRightShift)
+}
+
+// GetCursorShape returns the Cursor* constant for the shape the mouse cursor
+// should have for the last frame constructed.
+func (ui *Manager) GetCursorShape() int {
+	return ui.cursorShape
 }
 
 // bindOpenGLData sets the program, VAO, uniforms and attributes required for the
@@ -377,11 +856,11 @@ func (ui *Manager) Construct(frameDelta float64) {
 func (ui *Manager) bindOpenGLData(style *Style, view mgl.Mat4) {
 	const floatSize = 4
 	const uintSize = 4
-	const posOffset = 0
-	const uvOffset = floatSize * 2
-	const texIdxOffset = floatSize * 4
-	const colorOffset = floatSize * 5
-	const VBOStride = floatSize * (2 + 2 + 1 + 4) // vert / uv / texIndex / color
+	const posOffset = floatSize * VertexPositionOffset
+	const uvOffset = floatSize * VertexUVOffset
+	const texIdxOffset = floatSize * VertexTextureIndexOffset
+	const colorOffset = floatSize * VertexColorOffset
+	const VBOStride = floatSize * VertexStride
 
 	gfx := ui.gfx
 
@@ -437,17 +916,13 @@ func (ui *Manager) bindOpenGLData(style *Style, view mgl.Mat4) {
 // the actual draw call.
 func (ui *Manager) Draw() {
 	// short circuit the rendering if there are no windows to draw
-	if len(ui.windows) < 1 {
+	drawData := ui.DrawData()
+	if len(drawData.Windows) < 1 {
 		return
 	}
 
 	const floatSize = 4
 	const uintSize = 4
-	const posOffset = 0
-	const uvOffset = floatSize * 2
-	const texIdxOffset = floatSize * 4
-	const colorOffset = floatSize * 5
-	const VBOStride = floatSize * (2 + 2 + 1 + 4) // vert / uv / texIndex / color
 	gfx := ui.gfx
 
 	// FIXME: move the zdepth definitions elsewhere
@@ -460,24 +935,23 @@ func (ui *Manager) Draw() {
 	// for now, loop through all of the windows and copy all of the data into the manager's buffer
 	// FIXME: this could be buffered straight from the cmdList
 	var startIndex uint32
-	for _, w := range ui.windows {
-		for _, cmd := range w.cmds {
-			if cmd.isCustom {
+	for _, wdd := range drawData.Windows {
+		for _, cmd := range wdd.Commands {
+			if cmd.IsCustom {
 				continue
 			}
 
-			ui.comboBuffer = append(ui.comboBuffer, cmd.comboBuffer...)
+			ui.comboBuffer = append(ui.comboBuffer, cmd.Vertices...)
 
 			// reindex the index buffer to reference the correct vertex data
-			highestIndex := uint32(0)
-			for _, i := range cmd.indexBuffer {
-				if i > highestIndex {
-					highestIndex = i
-				}
+			for _, i := range cmd.Indices {
 				ui.indexBuffer = append(ui.indexBuffer, i+startIndex)
 			}
-			ui.faceCount += cmd.faceCount
-			startIndex += highestIndex + 1
+			ui.faceCount += cmd.FaceCount
+
+			// offset by the vertex count so that empty command lists don't
+			// shift the indexes of the ones that follow
+			startIndex += uint32(len(cmd.Vertices) / VertexStride)
 		}
 	}
 
@@ -500,24 +974,24 @@ func (ui *Manager) Draw() {
 
 	// loop through the windows and each window's draw cmd list
 	indexOffset := uint32(0)
-	for _, w := range ui.windows {
-		for _, cmd := range w.cmds {
-			gfx.Scissor(int32(cmd.clipRect[0]), int32(cmd.clipRect[1]-cmd.clipRect[3]), int32(cmd.clipRect[2]), int32(cmd.clipRect[3]))
+	for _, wdd := range drawData.Windows {
+		for _, cmd := range wdd.Commands {
+			gfx.Scissor(int32(cmd.ClipRect[0]), int32(cmd.ClipRect[1]-cmd.ClipRect[3]), int32(cmd.ClipRect[2]), int32(cmd.ClipRect[3]))
 
 			// for most widgets, isCustom will be false, so we just draw things how we have them bound and then
 			// update the index offset into the master combo and index buffers stored in Manager.
-			if cmd.isCustom == false {
+			if cmd.IsCustom == false {
 				if needRebinding {
 					// bind all of the uniforms and attributes
 					ui.bindOpenGLData(&DefaultStyle, view)
 					gfx.Viewport(0, 0, ui.width, ui.height)
 					needRebinding = false
 				}
-				gfx.DrawElements(graphics.TRIANGLES, int32(cmd.faceCount*3), graphics.UNSIGNED_INT, gfx.PtrOffset(int(indexOffset)*uintSize))
-				indexOffset += cmd.faceCount * 3
+				gfx.DrawElements(graphics.TRIANGLES, int32(cmd.FaceCount*3), graphics.UNSIGNED_INT, gfx.PtrOffset(int(indexOffset)*uintSize))
+				indexOffset += cmd.FaceCount * 3
 			} else {
-				gfx.Viewport(int32(cmd.clipRect[0]), int32(cmd.clipRect[1]-cmd.clipRect[3]), int32(cmd.clipRect[2]), int32(cmd.clipRect[3]))
-				cmd.onCustomDraw()
+				gfx.Viewport(int32(cmd.ClipRect[0]), int32(cmd.ClipRect[1]-cmd.ClipRect[3]), int32(cmd.ClipRect[2]), int32(cmd.ClipRect[3]))
+				cmd.OnCustomDraw()
 				needRebinding = true
 			}
 		}
@@ -0,0 +1,355 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"fmt"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// MainMenuBarID is the ID of the window that holds the main menu bar built
+// by Manager.MainMenuBar.
+const MainMenuBarID = "eweygewey#mainmenubar"
+
+// MenuItemOptions are the options for a menu item created with MenuItemAdv().
+type MenuItemOptions struct {
+	// Disabled draws the item with Style.MenuDisabledColor and ignores input.
+	Disabled bool
+
+	// Shortcut is a hint for the keyboard shortcut of the item, such as
+	// "Ctrl+S", which is drawn on the right side of the item with
+	// Style.MenuShortcutColor. The shortcut itself isn't handled.
+	Shortcut string
+
+	// Checked, if not nil, makes the item checkable; a check mark is drawn
+	// if it's true and picking the item toggles it.
+	Checked *bool
+}
+
+// closeMenus closes the menu being built along with the menus it was opened
+// from and their submenus.
+func (ui *Manager) closeMenus() {
+	if ui.buildingPopup == nil || !ui.buildingPopup.IsMenu {
+		return
+	}
+
+	i := ui.popupIndex(ui.buildingPopup.ID)
+	for i > 0 && ui.popups[i-1].IsMenu {
+		i--
+	}
+	ui.closePopupsFrom(i)
+}
+
+// constructMainMenuBar builds the main menu bar at the top of the screen with
+// the MainMenuBar callback or removes it if the callback isn't set.
+func (ui *Manager) constructMainMenuBar() {
+	if ui.MainMenuBar == nil {
+		ui.mainMenuBar = nil
+		return
+	}
+
+	if ui.mainMenuBar == nil {
+		ui.mainMenuBar = newWindow(MainMenuBarID, 0.0, 1.0, 1.0, 0.0, nil)
+		ui.mainMenuBar.Owner = ui
+		ui.mainMenuBar.ShowTitleBar = false
+		ui.mainMenuBar.IsMoveable = false
+	}
+	ui.mainMenuBar.MenuBar = ui.MainMenuBar
+	ui.mainMenuBar.construct()
+}
+
+// GetMainMenuBarHeight returns the height of the main menu bar in display
+// coordinates or zero if there isn't one.
+func (ui *Manager) GetMainMenuBarHeight() float32 {
+	if ui.mainMenuBar == nil {
+		return 0.0
+	}
+	return ui.mainMenuBar.menuBarHeight()
+}
+
+// closeSubmenus closes any popups opened from the menu being built.
+func (ui *Manager) closeSubmenus() {
+	if ui.buildingPopup == nil || !ui.buildingPopup.IsMenu {
+		return
+	}
+	ui.closePopupsFrom(ui.popupIndex(ui.buildingPopup.ID) + 1)
+}
+
+// ContextMenu opens a menu at the mouse position when the last widget built
+// is clicked with the right mouse button. The constructor builds the items of
+// the menu with MenuItem(), Menu() and Separator(). Like a Popup(), it needs
+// to be called every frame and it closes when the mouse is pressed outside of
+// it, Escape is pressed or one of its items is picked.
+// Returns true if the menu is open.
+func (wnd *Window) ContextMenu(id string, constructor BuildCallback) bool {
+	ui := wnd.Owner
+	rmbStatus := ui.GetMouseButtonAction(1)
+	if rmbStatus == MouseClick || rmbStatus == MouseDoubleClick {
+		mx, my := wnd.getMouseDownPosition(1)
+		r := wnd.lastItemRect
+		if mx > r[0] && my > r[1]-r[3] && mx < r[0]+r[2] && my < r[1] {
+			ui.ClearMouseButtonAction(1)
+			ui.OpenPopup(id)
+		}
+	}
+
+	return wnd.menuPopup(id, constructor) != nil
+}
+
+// menuPopup declares the menu popup with the given id for this frame if it's
+// open and returns its state; it returns nil if the menu isn't open. Menus
+// are as wide as their widest item.
+func (wnd *Window) menuPopup(id string, constructor BuildCallback) *popupState {
+	p := wnd.popup(id, false, "", 0.0, constructor)
+	if p == nil {
+		return nil
+	}
+	p.IsMenu = true
+	p.Window.AutoAdjustWidth = true
+	return p
+}
+
+// MenuItem draws an item of a menu on a row of its own that highlights across
+// the width of the window when hovered. Returns true if it was clicked or
+// activated with the keyboard, which also closes the menu it's in.
+func (wnd *Window) MenuItem(id string, text string) (bool, error) {
+	return wnd.MenuItemAdv(id, text, MenuItemOptions{})
+}
+
+// MenuItemAdv draws an item of a menu like MenuItem() with the options given.
+func (wnd *Window) MenuItemAdv(id string, text string, options MenuItemOptions) (bool, error) {
+	_, hovered, pressed, err := wnd.menuItem(id, text, false, options)
+	if err != nil {
+		return false, err
+	}
+
+	// hovering another item closes the submenu that's open
+	if hovered {
+		wnd.Owner.closeSubmenus()
+	}
+	if pressed {
+		if options.Checked != nil {
+			*options.Checked = !*options.Checked
+		}
+		wnd.Owner.closeMenus()
+	}
+	return pressed, nil
+}
+
+// Menu draws an item of a menu with an arrow that opens a submenu to the right
+// of it when hovered or activated. The constructor builds the items of the
+// submenu. When called from a MenuBar callback, it draws a menu in the menu bar
+// instead that opens below it when clicked, or when hovered while another menu
+// of the bar is open. Returns true if the submenu is open.
+func (wnd *Window) Menu(id string, text string, constructor BuildCallback) (bool, error) {
+	if wnd.buildingMenuBar {
+		return wnd.menuBarItem(id, text, constructor)
+	}
+
+	rect, hovered, pressed, err := wnd.menuItem(id, text, true, MenuItemOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	if hovered || pressed {
+		frameX, _, frameW, _ := wnd.GetFrameSize()
+		wnd.Owner.openPopupAt(id, frameX+frameW, rect[1]+wnd.Style.WindowPadding[2])
+	}
+
+	return wnd.menuPopup(id, constructor) != nil, nil
+}
+
+// menuItem draws an item of a menu and returns its area as [x,y,w,h] in
+// display coordinates, whether or not the mouse is over it and whether or not
+// it was clicked or activated with the keyboard.
+func (wnd *Window) menuItem(id string, text string, hasSubmenu bool, options MenuItemOptions) (mgl.Vec4, bool, bool, error) {
+	wnd.StartRow()
+	cmd := wnd.getLastCmd()
+
+	// get the font for the text
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return mgl.Vec4{}, false, false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	// calculate the size necessary for the item; the highlight spans the
+	// width of the window but only the text, shortcut hint, check mark and
+	// submenu arrow count towards its width.
+	pos := wnd.getCursorDC()
+	dimX, dimY, _ := font.GetRenderSize(text)
+	itemH := dimY + wnd.Style.MenuPadding[2] + wnd.Style.MenuPadding[3]
+	itemW := dimX + wnd.Style.MenuPadding[0] + wnd.Style.MenuPadding[1]
+	var shortcutW float32
+	if len(options.Shortcut) > 0 {
+		shortcutW, _, _ = font.GetRenderSize(options.Shortcut)
+		itemW += shortcutW + wnd.Style.MenuPadding[0] + wnd.Style.MenuPadding[1]
+	}
+	if hasSubmenu || options.Checked != nil {
+		itemW += itemH
+	}
+	_, _, wndWidth, _ := wnd.GetDisplaySize()
+	highlightW := wndWidth - wnd.widgetCursorDC[0] - wnd.Style.WindowPadding[1]
+	if highlightW < itemW {
+		highlightW = itemW
+	}
+
+	hovered, pressed := false, false
+	textColor := wnd.Style.MenuTextColor
+	if options.Disabled {
+		textColor = wnd.Style.MenuDisabledColor
+	} else {
+		focused, activated := wnd.focusBehavior(id)
+		buttonTest := wnd.buttonBehavior(id, pos[0], pos[1], highlightW, itemH)
+		hovered = buttonTest != buttonNoAction
+		pressed = activated || buttonTest == buttonPressed
+		if buttonTest == buttonPressed {
+			wnd.Owner.ClearMouseButtonAction(0)
+		}
+
+		if hovered || (focused && wnd.Owner.focusVisible) {
+			combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+highlightW, pos[1]-itemH, wnd.Style.MenuHoverColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			cmd.AddFaces(combos, indexes, fc)
+		}
+	}
+
+	// draw the text, the arrow for submenus or the check mark and then the
+	// shortcut hint, all right aligned after the text.
+	textPos := pos
+	textPos[0] += wnd.Style.MenuPadding[0]
+	textPos[1] -= wnd.Style.MenuPadding[2]
+	renderData := font.CreateText(textPos, textColor, text)
+	cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+
+	markSize := itemH * 0.25
+	rightX := pos[0] + highlightW - wnd.Style.MenuPadding[1]
+	if hasSubmenu {
+		combos, indexes, fc := cmd.drawTreeNodeIcon(false, rightX-markSize, pos[1]-itemH*0.5+markSize, rightX, pos[1]-itemH*0.5-markSize, textColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+		rightX -= itemH
+	} else if options.Checked != nil {
+		if *options.Checked {
+			combos, indexes, fc := cmd.DrawRectFilledDC(rightX-markSize*2, pos[1]-itemH*0.5+markSize, rightX, pos[1]-itemH*0.5-markSize, textColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			cmd.AddFaces(combos, indexes, fc)
+		}
+		rightX -= itemH
+	}
+
+	if len(options.Shortcut) > 0 {
+		shortcutColor := wnd.Style.MenuShortcutColor
+		if options.Disabled {
+			shortcutColor = wnd.Style.MenuDisabledColor
+		}
+		shortcutPos := textPos
+		shortcutPos[0] = rightX - shortcutW
+		renderData = font.CreateText(shortcutPos, shortcutColor, options.Shortcut)
+		cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+	}
+
+	// advance the cursor and start the next row
+	wnd.addCursorHorizontalDelta(itemW)
+	wnd.setNextRowCursorOffset(itemH)
+	wnd.lastItemRect[2] = highlightW
+	wnd.StartRow()
+
+	return mgl.Vec4{pos[0], pos[1], highlightW, itemH}, hovered, pressed, nil
+}
+
+// menuBarHeight returns the height of the menu bar in display coordinates or
+// zero if the window doesn't have one.
+func (wnd *Window) menuBarHeight() float32 {
+	if wnd.MenuBar == nil {
+		return 0.0
+	}
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return 0.0
+	}
+	_, dimY, _ := font.GetRenderSize("0.0")
+	return dimY + wnd.Style.MenuPadding[2] + wnd.Style.MenuPadding[3]
+}
+
+// buildMenuBar draws the menu bar below the title bar and calls MenuBar to
+// build its menus.
+func (wnd *Window) buildMenuBar() {
+	x, y, w, _ := wnd.GetFrameSize()
+	y -= wnd.titleBarHeight()
+	cmd := wnd.addNewCmd()
+	combos, indexes, fc := cmd.DrawRectFilledDC(x, y, x+w, y-wnd.menuBarHeight(), wnd.Style.MenuBarBgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+
+	wnd.menuBarCursorDC = mgl.Vec2{x + wnd.Style.WindowPadding[0], y}
+	wnd.buildingMenuBar = true
+	wnd.MenuBar(wnd)
+	wnd.buildingMenuBar = false
+
+	// remember if one of the menus is open so that hovering the others opens them
+	wnd.menuBarOpen = false
+	for _, p := range wnd.Owner.popups {
+		if p.MenuBar == wnd {
+			wnd.menuBarOpen = true
+		}
+	}
+	wnd.addNewCmd()
+}
+
+// menuBarItem draws a menu in the menu bar that opens its dropdown below it.
+// See Menu().
+func (wnd *Window) menuBarItem(id string, text string, constructor BuildCallback) (bool, error) {
+	ui := wnd.Owner
+	cmd := wnd.getLastCmd()
+
+	// get the font for the text
+	font := ui.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	// calculate the size necessary for the menu
+	pos := wnd.menuBarCursorDC
+	dimX, dimY, _ := font.GetRenderSize(text)
+	itemW := dimX + wnd.Style.MenuPadding[0] + wnd.Style.MenuPadding[1]
+	itemH := dimY + wnd.Style.MenuPadding[2] + wnd.Style.MenuPadding[3]
+
+	// clicking the menu toggles it and while another menu of the bar is open,
+	// hovering it opens it instead.
+	isOpen := ui.IsPopupOpen(id)
+	_, activated := wnd.focusBehavior(id)
+	buttonTest := wnd.buttonBehavior(id, pos[0], pos[1], itemW, itemH)
+	if buttonTest == buttonPressed {
+		ui.ClearMouseButtonAction(0)
+	}
+	open := false
+	if activated || buttonTest == buttonPressed {
+		if isOpen {
+			ui.ClosePopup(id)
+			isOpen = false
+		} else {
+			open = true
+		}
+	} else if buttonTest == buttonHover && wnd.menuBarOpen && !isOpen {
+		open = true
+	}
+	if open {
+		// pressing the mouse on the menu doesn't close the dropdown so that
+		// clicking it can.
+		p := ui.openPopupAt(id, pos[0], pos[1]-itemH)
+		p.OwnerRect = mgl.Vec4{pos[0], pos[1], itemW, itemH}
+		p.MenuBar = wnd
+		isOpen = true
+	}
+
+	// draw the menu highlighted if it's open or hovered
+	if isOpen || buttonTest != buttonNoAction {
+		combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+itemW, pos[1]-itemH, wnd.Style.MenuHoverColor, defaultTextureSampler, ui.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+	}
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], itemW, itemH)
+	textPos := mgl.Vec3{pos[0] + wnd.Style.MenuPadding[0], pos[1] - wnd.Style.MenuPadding[2], 0.0}
+	renderData := font.CreateText(textPos, wnd.Style.MenuTextColor, text)
+	cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+
+	wnd.menuBarCursorDC[0] += itemW
+	return wnd.menuPopup(id, constructor) != nil, nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// popupState is the state of a popup opened with Manager.OpenPopup().
+type popupState struct {
+	// ID is the ID of the popup, which is also the ID of its window.
+	ID string
+
+	// Modal is true if the popup blocks input to everything under it.
+	Modal bool
+
+	// IsMenu is true if the popup is a menu, which closes along with the
+	// menus it was opened from when one of its items is picked.
+	IsMenu bool
+
+	// MenuBar is the window whose menu bar opened the popup or nil if it
+	// wasn't opened from a menu bar.
+	MenuBar *Window
+
+	// Anchor is where the top-left corner of a popup that isn't modal is
+	// placed, in display coordinates.
+	Anchor mgl.Vec2
+
+	// OwnerRect is an optional area as [x,y,w,h] in display coordinates where
+	// pressing the mouse doesn't close the popup, such as the widget that
+	// toggles it.
+	OwnerRect mgl.Vec4
+
+	// WidthDC is the width of the popup in display coordinates.
+	WidthDC float32
+
+	// Alive is set when the popup is opened or built so that it closes if
+	// the code building it stops.
+	Alive bool
+
+	// Window is the window the contents of the popup are built in.
+	Window *Window
+}
+
+// ownerContains returns true if the display coordinate is inside of OwnerRect.
+func (p *popupState) ownerContains(x, y float32) bool {
+	r := p.OwnerRect
+	return x > r[0] && y > r[1]-r[3] && x < r[0]+r[2] && y < r[1]
+}
+
+// OpenPopup opens the popup with the given id at the mouse position. Its
+// contents are built by the Window.Popup() or Window.PopupModal() call with
+// the same id, which needs to be made every frame while the popup is open.
+// Opening a popup while another popup is being built stacks it on top of
+// that one; otherwise any popups that aren't modal are closed first.
+func (ui *Manager) OpenPopup(id string) {
+	mx, my := ui.GetMousePosition()
+	ui.openPopupAt(id, mx, my)
+}
+
+// ClosePopup closes the popup with the given id and any popups stacked on
+// top of it.
+func (ui *Manager) ClosePopup(id string) {
+	if i := ui.popupIndex(id); i >= 0 {
+		ui.closePopupsFrom(i)
+	}
+}
+
+// IsPopupOpen returns true if the popup with the given id is open.
+func (ui *Manager) IsPopupOpen(id string) bool {
+	return ui.popupIndex(id) >= 0
+}
+
+// openPopupAt opens the popup with the given id with its top-left corner at
+// (x,y) in display coordinates and returns its state. If the popup is
+// already open, the existing state is returned.
+func (ui *Manager) openPopupAt(id string, x, y float32) *popupState {
+	if i := ui.popupIndex(id); i >= 0 {
+		return ui.popups[i]
+	}
+
+	// stack the popup on top of the one being built or the top-most modal
+	if ui.buildingPopup != nil {
+		ui.closePopupsFrom(ui.popupIndex(ui.buildingPopup.ID) + 1)
+	} else {
+		ui.closePopupsFrom(ui.topModalIndex() + 1)
+	}
+
+	p := new(popupState)
+	p.ID = id
+	p.Anchor = mgl.Vec2{x, y}
+	p.Alive = true
+	p.Window = newWindow(id, 0, 0, 0, 0, nil)
+	p.Window.Owner = ui
+	p.Window.ShowTitleBar = false
+	p.Window.IsMoveable = false
+	p.Window.AutoAdjustHeight = true
+	ui.popups = append(ui.popups, p)
+	return p
+}
+
+// popupIndex returns the index of the open popup with the given id in the
+// popup stack or -1 if it's not open.
+func (ui *Manager) popupIndex(id string) int {
+	for i, p := range ui.popups {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// topModalIndex returns the index of the top-most modal popup in the popup
+// stack or -1 if there are no modal popups open.
+func (ui *Manager) topModalIndex() int {
+	for i := len(ui.popups) - 1; i >= 0; i-- {
+		if ui.popups[i].Modal {
+			return i
+		}
+	}
+	return -1
+}
+
+// closePopupsFrom closes the popups in the popup stack from the index up.
+func (ui *Manager) closePopupsFrom(index int) {
+	if index < 0 || index >= len(ui.popups) {
+		return
+	}
+	for i := index; i < len(ui.popups); i++ {
+		ui.popups[i] = nil
+	}
+	ui.popups = ui.popups[:index]
+}
+
+// updatePopups is called at the start of each frame after the mouse owner has
+// been picked. Pressing a mouse button outside of the popups closes the ones
+// that aren't modal and if a modal popup is open, it claims the active input
+// for a left button press so that no widget under it can.
+func (ui *Manager) updatePopups() {
+	if len(ui.popups) == 0 {
+		return
+	}
+
+	var mx, my float32
+	if ui.lmbPressed {
+		mx, my = ui.GetMouseDownPosition(0)
+	} else if ui.rmbPressed {
+		mx, my = ui.GetMouseDownPosition(1)
+	} else {
+		return
+	}
+
+	// pressing in the overlay, such as the list of a combo box in a popup,
+	// doesn't count as pressing outside of the popup.
+	if ui.overlayContains(mx, my) {
+		return
+	}
+
+	keep := 0
+	for i := len(ui.popups) - 1; i >= 0; i-- {
+		p := ui.popups[i]
+		if p.Modal || p.Window.ContainsPosition(mx, my) || p.ownerContains(mx, my) {
+			keep = i + 1
+			break
+		}
+	}
+	ui.closePopupsFrom(keep)
+
+	if topModal := ui.topModalIndex(); topModal >= 0 && ui.lmbPressed && ui.mouseOwner == nil {
+		ui.SetActiveInputID(ui.popups[topModal].ID)
+	}
+}
+
+// constructPopups builds the open popups that were declared this frame in the
+// order they were opened and closes the ones that weren't. Escape closes the
+// top-most popup if none of its widgets used the key.
+func (ui *Manager) constructPopups() {
+	topModal := ui.topModalIndex()
+	for i := 0; i < len(ui.popups); i++ {
+		p := ui.popups[i]
+		if !p.Alive {
+			ui.closePopupsFrom(i)
+			break
+		}
+		p.Alive = false
+
+		// the widgets in and above the top-most modal can have the focus
+		if i >= topModal {
+			ui.focusBlocked = false
+		}
+
+		// a popup opened before it was declared gets built next frame
+		pw := p.Window
+		pw.cmds = pw.cmds[:0]
+		if pw.OnBuild == nil {
+			continue
+		}
+
+		ui.placePopup(p)
+		ui.buildingPopup = p
+		pw.construct()
+		ui.buildingPopup = nil
+
+		// the popup may have closed itself while it was built
+		if i >= len(ui.popups) || ui.popups[i] != p {
+			break
+		}
+
+		if i == len(ui.popups)-1 && ui.consumeKey(EweyKeyEscape) {
+			ui.closePopupsFrom(i)
+			break
+		}
+
+		// dim everything under a modal popup
+		if p.Modal {
+			w, h := ui.GetResolution()
+			dim := newCmdList()
+			dim.clipRect = mgl.Vec4{0, float32(h), float32(w), float32(h)}
+			combos, indexes, fc := dim.DrawRectFilledDC(0, float32(h), float32(w), 0, pw.Style.PopupDimColor, defaultTextureSampler, ui.whitePixelUv)
+			dim.AddFaces(combos, indexes, fc)
+			pw.cmds = append([]*cmdList{dim}, pw.cmds...)
+		}
+	}
+	ui.focusBlocked = false
+}
+
+// placePopup sizes the popup window and places modal popups in the center of
+// the screen and the others at their anchor, keeping them on the screen.
+func (ui *Manager) placePopup(p *popupState) {
+	pw := p.Window
+	if !pw.AutoAdjustWidth {
+		pw.Width, _ = ui.DisplayToScreen(p.WidthDC, 0.0)
+	}
+	_, _, _, frameHDC := pw.GetFrameSize()
+	_, frameH := ui.DisplayToScreen(0.0, frameHDC)
+
+	if p.Modal {
+		pw.Location[0] = (1.0 - pw.Width) * 0.5
+		pw.Location[1] = (1.0 + frameH) * 0.5
+	} else {
+		pw.Location[0], pw.Location[1] = ui.DisplayToScreen(p.Anchor[0], p.Anchor[1])
+	}
+
+	if pw.Location[0]+pw.Width > 1.0 {
+		pw.Location[0] = 1.0 - pw.Width
+	}
+	if pw.Location[0] < 0.0 {
+		pw.Location[0] = 0.0
+	}
+	if pw.Location[1]-frameH < 0.0 {
+		pw.Location[1] = frameH
+	}
+	if pw.Location[1] > 1.0 {
+		pw.Location[1] = 1.0
+	}
+}
+
+// Popup builds the contents of the popup with the given id with the
+// constructor if the popup was opened with Manager.OpenPopup(). The popup is
+// placed where the mouse was when it was opened, is Style.PopupWidth wide and
+// as tall as its widgets. Pressing the mouse outside of it or Escape closes
+// it. This needs to be called every frame; the popup closes if it isn't.
+// Returns true if the popup is open.
+func (wnd *Window) Popup(id string, constructor BuildCallback) bool {
+	return wnd.popup(id, false, "", wnd.Style.PopupWidth, constructor) != nil
+}
+
+// PopupModal builds the contents of a modal popup like Popup() does, but the
+// popup is centered on the screen with the title shown in a title bar and the
+// rest of the user interface is dimmed with Style.PopupDimColor and gets no
+// input until the popup is closed with Manager.ClosePopup() or Escape.
+func (wnd *Window) PopupModal(id string, title string, constructor BuildCallback) bool {
+	return wnd.popup(id, true, title, wnd.Style.PopupWidth, constructor) != nil
+}
+
+// popup declares the popup with the given id for this frame if it's open and
+// returns its state; it returns nil if the popup isn't open.
+func (wnd *Window) popup(id string, modal bool, title string, widthDC float32, constructor BuildCallback) *popupState {
+	i := wnd.Owner.popupIndex(id)
+	if i < 0 {
+		return nil
+	}
+
+	p := wnd.Owner.popups[i]
+	p.Alive = true
+	p.Modal = modal
+	p.WidthDC = widthDC
+
+	pw := p.Window
+	pw.Style = wnd.Style
+	pw.Style.WindowBgColor = wnd.Style.PopupBgColor
+	pw.Title = title
+	pw.ShowTitleBar = modal
+	pw.OnBuild = constructor
+	return p
+}
@@ -0,0 +1,327 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/eweygewey/softgfx"
+	graphics "github.com/tbogdala/fizzle/graphicsprovider"
+	imgfont "golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// run the tests with -update to write new golden images to testdata
+var updateGolden = flag.Bool("update", false, "update the golden images in testdata")
+
+const (
+	testWidth       = 200
+	testHeight      = 150
+	testGlyphWidth  = 7
+	testGlyphHeight = 12
+	testGlyphAscent = 9
+	testFontTexSize = 128
+	testFontCellW   = 8
+	testFontCellH   = 14
+	testGoldenFuzz  = 2 // the difference allowed in each color channel
+)
+
+// testFace is a fixed size font face so that the text in the golden images
+// doesn't change with the version of freetype or the font file.
+type testFace struct{}
+
+func (testFace) Close() error { return nil }
+
+func (testFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return image.Rectangle{}, nil, image.Point{}, fixed.I(testGlyphWidth), false
+}
+
+func (testFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	bounds := fixed.Rectangle26_6{
+		Min: fixed.Point26_6{X: 0, Y: -fixed.I(testGlyphAscent)},
+		Max: fixed.Point26_6{X: fixed.I(testGlyphWidth), Y: fixed.I(testGlyphHeight - testGlyphAscent)},
+	}
+	return bounds, fixed.I(testGlyphWidth), true
+}
+
+func (testFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return fixed.I(testGlyphWidth), true
+}
+
+func (testFace) Kern(r0, r1 rune) fixed.Int26_6 { return 0 }
+
+func (testFace) Metrics() imgfont.Metrics {
+	return imgfont.Metrics{
+		Height:  fixed.I(testGlyphHeight),
+		Ascent:  fixed.I(testGlyphAscent),
+		Descent: fixed.I(testGlyphHeight - testGlyphAscent),
+	}
+}
+
+// newTestFont creates a font for the printable ASCII characters with the
+// testFace metrics and a blocky pattern for each glyph that is different
+// for every character, then registers it as the default font.
+func newTestFont(ui *Manager) *Font {
+	f := new(Font)
+	f.Owner = ui
+	f.face = testFace{}
+	f.locations = make(map[rune]runeData)
+
+	fontImg := image.NewRGBA(image.Rect(0, 0, testFontTexSize, testFontTexSize))
+	fontRowSize := testFontTexSize / testFontCellW
+	for i := 0; i < 95; i++ {
+		ch := rune(' ' + i)
+		imgX := (i % fontRowSize) * testFontCellW
+		imgY := (i / fontRowSize) * testFontCellH
+		f.Glyphs += string(ch)
+		f.locations[ch] = runeData{
+			imgX, imgY,
+			testGlyphWidth, 0,
+			testGlyphAscent, 0,
+			float32(imgX) / testFontTexSize, float32(imgY+testGlyphHeight) / testFontTexSize,
+			float32(imgX+testGlyphWidth) / testFontTexSize, float32(imgY) / testFontTexSize,
+		}
+
+		// the glyph is a 5x8 block of pixels picked by the character code
+		if ch == ' ' {
+			continue
+		}
+		for y := 0; y < 8; y++ {
+			bits := (int(ch)*(y*7+3) + y*y*11) | 0x11
+			for x := 0; x < 5; x++ {
+				if bits&(1<<uint(x)) != 0 {
+					fontImg.SetRGBA(imgX+1+x, imgY+2+y, color.RGBA{255, 255, 255, 255})
+				}
+			}
+		}
+	}
+
+	// set the white point
+	fontImg.SetRGBA(testFontTexSize-1, testFontTexSize-1, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+	f.TextureSize = testFontTexSize
+	f.GlyphWidth = testGlyphWidth
+	f.GlyphHeight = testGlyphHeight
+	f.Texture = f.loadRGBAToTexture(fontImg.Pix, testFontTexSize)
+	ui.fonts[DefaultStyle.FontName] = f
+	return f
+}
+
+// newTestTexture creates a 2x2 texture with the four colors in the order
+// the texels are stored, which is bottom-left, bottom-right, top-left and
+// top-right when drawn with a uvPair of {0,0,1,1}.
+func newTestTexture(gfx graphics.GraphicsProvider, colors [4]color.RGBA) graphics.Texture {
+	pix := make([]byte, 0, 16)
+	for _, c := range colors {
+		pix = append(pix, c.R, c.G, c.B, c.A)
+	}
+	tex := gfx.GenTexture()
+	gfx.ActiveTexture(graphics.TEXTURE0)
+	gfx.BindTexture(graphics.TEXTURE_2D, tex)
+	gfx.TexParameteri(graphics.TEXTURE_2D, graphics.TEXTURE_MAG_FILTER, graphics.NEAREST)
+	gfx.TexParameteri(graphics.TEXTURE_2D, graphics.TEXTURE_MIN_FILTER, graphics.NEAREST)
+	gfx.TexParameteri(graphics.TEXTURE_2D, graphics.TEXTURE_WRAP_S, graphics.CLAMP_TO_EDGE)
+	gfx.TexParameteri(graphics.TEXTURE_2D, graphics.TEXTURE_WRAP_T, graphics.CLAMP_TO_EDGE)
+	gfx.TexImage2D(graphics.TEXTURE_2D, 0, graphics.RGBA, 2, 2, 0, graphics.RGBA, graphics.UNSIGNED_BYTE, gfx.Ptr(pix), len(pix))
+	return tex
+}
+
+// renderScene creates a Manager drawn by softgfx, lets setup add windows to
+// it and returns the image of the second frame drawn.
+func renderScene(t *testing.T, setup func(ui *Manager)) *image.RGBA {
+	gfx := softgfx.NewGraphicsImpl(testWidth, testHeight)
+	ui := NewManager(gfx)
+	if err := ui.Initialize(VertShader330, FragShader330, testWidth, testHeight, testHeight); err != nil {
+		t.Fatalf("Failed to initialize the Manager: %v", err)
+	}
+	newTestFont(ui)
+
+	// keep the mouse away from the widgets
+	ui.GetMousePosition = func() (float32, float32) { return -1, -1 }
+	ui.GetMouseDownPosition = func(buttonNumber int) (float32, float32) { return -1, -1 }
+	ui.GetScrollWheelDelta = func(bool) float32 { return 0 }
+	ui.GetKeyEvents = func() []KeyPressEvent { return nil }
+	ui.ClearKeyEvents = func() {}
+	ui.IsKeyDown = func(keyCode int) bool { return false }
+
+	setup(ui)
+
+	gfx.Enable(graphics.BLEND)
+	for i := 0; i < 2; i++ {
+		ui.Construct(1.0 / 60.0)
+		gfx.ClearColor(0.1, 0.1, 0.1, 1.0)
+		gfx.Clear(graphics.COLOR_BUFFER_BIT)
+		ui.Draw()
+	}
+	return gfx.Image()
+}
+
+// compareGolden compares the image to the golden image in testdata or writes
+// it there if the tests are run with -update.
+func compareGolden(t *testing.T, name string, img *image.RGBA) {
+	goldenPath := filepath.Join("testdata", name+".png")
+	if *updateGolden {
+		if err := writePNG(goldenPath, img); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	f, err := os.Open(goldenPath)
+	if err != nil {
+		t.Fatalf("Failed to open the golden image: %v", err)
+	}
+	defer f.Close()
+	golden, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("Failed to decode the golden image: %v", err)
+	}
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("The image is %v but the golden image is %v.", img.Bounds(), golden.Bounds())
+	}
+
+	mismatches := 0
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			want := color.RGBAModel.Convert(golden.At(x, y)).(color.RGBA)
+			got := img.RGBAAt(x, y)
+			if !channelsClose(want.R, got.R) || !channelsClose(want.G, got.G) ||
+				!channelsClose(want.B, got.B) || !channelsClose(want.A, got.A) {
+				if mismatches == 0 {
+					t.Errorf("The pixel at (%d,%d) is %v but should be %v.", x, y, got, want)
+				}
+				mismatches++
+			}
+		}
+	}
+	if mismatches > 0 {
+		failedPath := filepath.Join(os.TempDir(), "eweygewey_"+name+".png")
+		writePNG(failedPath, img)
+		t.Errorf("%d pixels differ from %s; the image was written to %s.", mismatches, goldenPath, failedPath)
+	}
+}
+
+func channelsClose(a, b uint8) bool {
+	d := int(a) - int(b)
+	return d >= -testGoldenFuzz && d <= testGoldenFuzz
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
+
+func TestRenderGolden(t *testing.T) {
+	white := mgl.Vec4{1, 1, 1, 1}
+	wholeImage := mgl.Vec4{0, 0, 1, 1}
+
+	scenes := []struct {
+		name  string
+		setup func(ui *Manager)
+	}{
+		{
+			// the last button is wider than the window so it gets clipped
+			name: "button",
+			setup: func(ui *Manager) {
+				wnd := ui.NewWindow("buttons", 0.05, 0.95, 0.6, 0.8, func(wnd *Window) {
+					wnd.Button("ok", "Okay")
+					wnd.Button("cancel", "Cancel")
+					wnd.StartRow()
+					wnd.Button("wide", "Far too wide for the window")
+				})
+				wnd.ShowTitleBar = false
+			},
+		},
+		{
+			name: "editbox",
+			setup: func(ui *Manager) {
+				short := "Hello!"
+				long := "The quick brown fox jumps over the lazy dog"
+				wnd := ui.NewWindow("editboxes", 0.05, 0.95, 0.8, 0.8, func(wnd *Window) {
+					wnd.Editbox("short", &short)
+					wnd.StartRow()
+					wnd.Editbox("long", &long)
+				})
+				wnd.ShowTitleBar = false
+			},
+		},
+		{
+			name: "treenode",
+			setup: func(ui *Manager) {
+				wnd := ui.NewWindow("tree", 0.05, 0.95, 0.8, 0.8, func(wnd *Window) {
+					if open, _ := wnd.TreeNode("open", "Open node"); open {
+						wnd.Indent()
+						wnd.StartRow()
+						wnd.Text("Child")
+						wnd.Unindent()
+					}
+					wnd.StartRow()
+					wnd.TreeNode("closed", "Closed node")
+				})
+				wnd.ShowTitleBar = false
+				wnd.setStoredInt("open", 1)
+			},
+		},
+		{
+			name: "titlebar",
+			setup: func(ui *Manager) {
+				wnd := ui.NewWindow("titled", 0.05, 0.95, 0.8, 0.4, func(wnd *Window) {
+					wnd.Text("Inside")
+				})
+				wnd.Title = "Title"
+				wnd.IsCollapsible = true
+				wnd.ShowCloseButton = true
+
+				collapsed := ui.NewWindow("collapsed", 0.05, 0.4, 0.8, 0.3, func(wnd *Window) {
+					wnd.Text("Hidden")
+				})
+				collapsed.Title = "Collapsed"
+				collapsed.IsCollapsible = true
+				collapsed.IsCollapsed = true
+			},
+		},
+		{
+			// the images are drawn with the TEX[1] to TEX[3] samplers and
+			// the text with TEX[0]
+			name: "textures",
+			setup: func(ui *Manager) {
+				red := color.RGBA{255, 0, 0, 255}
+				green := color.RGBA{0, 255, 0, 255}
+				blue := color.RGBA{0, 0, 255, 255}
+				yellow := color.RGBA{255, 255, 0, 255}
+				textures := []graphics.Texture{
+					newTestTexture(ui.gfx, [4]color.RGBA{red, green, blue, yellow}),
+					newTestTexture(ui.gfx, [4]color.RGBA{green, blue, yellow, red}),
+					newTestTexture(ui.gfx, [4]color.RGBA{blue, yellow, red, green}),
+				}
+				wnd := ui.NewWindow("images", 0.05, 0.95, 0.9, 0.8, func(wnd *Window) {
+					wnd.Text("Images")
+					wnd.StartRow()
+					for i, tex := range textures {
+						wnd.Image(string(rune('a'+i)), 0.25, 0.25, white, ui.AddTextureToStack(tex), wholeImage)
+					}
+				})
+				wnd.ShowTitleBar = false
+			},
+		},
+	}
+
+	for _, scene := range scenes {
+		t.Run(scene.name, func(t *testing.T) {
+			compareGolden(t, scene.name, renderScene(t, scene.setup))
+		})
+	}
+}
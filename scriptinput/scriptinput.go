@@ -0,0 +1,376 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package scriptinput
+
+/*
+Package scriptinput implements the input hooks of the Manager by replaying a
+timeline of scripted input events instead of polling a real window. This
+makes it possible to write deterministic tests for widgets.
+
+Frames are counted by calls to Manager.Construct(); the first call is frame 0.
+Events scheduled for a frame are applied at the start of that frame's
+construction. Mouse positions are in the same display coordinates the
+Manager uses, with the origin in the lower left corner.
+
+Button clicks follow the same rules as the glfwinput package: a button that
+is pressed on frame N and released on frame N+1 reports MouseDown on frame N
+and MouseClick on frame N+1. Two clicks within the double click threshold
+report MouseDoubleClick on the second release.
+*/
+
+import (
+	"time"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	gui "github.com/tbogdala/eweygewey"
+)
+
+// the types of events that can be in a script
+const (
+	eventMouseMove = iota
+	eventMouseDown
+	eventMouseUp
+	eventScroll
+	eventKey
+	eventKeyDown
+	eventKeyUp
+)
+
+// event is a single scripted input event.
+type event struct {
+	frame  int
+	kind   int
+	x, y   float32
+	button int
+	delta  float32
+	key    gui.KeyPressEvent
+}
+
+// Script is a timeline of input events that get replayed frame by frame.
+type Script struct {
+	// FrameDuration is the simulated amount of time each frame takes. It is
+	// used to detect double clicks so that results don't depend on how fast
+	// the test runs.
+	FrameDuration time.Duration
+
+	// Clipboard is the content of the simulated clipboard.
+	Clipboard string
+
+	// CursorShape is the last cursor shape the Manager asked for, which is
+	// one of the gui.Cursor* constants.
+	CursorShape int
+
+	events []event
+	frame  int
+}
+
+// NewScript creates a new, empty script that simulates 60 frames per second.
+func NewScript() *Script {
+	s := new(Script)
+	s.FrameDuration = time.Second / 60
+	s.frame = -1
+	return s
+}
+
+// Frame returns the number of the frame currently being constructed or -1
+// if the Manager hasn't constructed a frame yet.
+func (s *Script) Frame() int {
+	return s.frame
+}
+
+// addEvent appends the event to the timeline and returns the script for chaining.
+func (s *Script) addEvent(e event) *Script {
+	s.events = append(s.events, e)
+	return s
+}
+
+// MoveMouse moves the mouse to (x,y) on the given frame.
+func (s *Script) MoveMouse(frame int, x, y float32) *Script {
+	return s.addEvent(event{frame: frame, kind: eventMouseMove, x: x, y: y})
+}
+
+// PressMouse presses the mouse button on the given frame.
+func (s *Script) PressMouse(frame int, button int) *Script {
+	return s.addEvent(event{frame: frame, kind: eventMouseDown, button: button})
+}
+
+// ReleaseMouse releases the mouse button on the given frame.
+func (s *Script) ReleaseMouse(frame int, button int) *Script {
+	return s.addEvent(event{frame: frame, kind: eventMouseUp, button: button})
+}
+
+// Click moves the mouse to (x,y) and presses the button on the given frame
+// and then releases it on the next frame, which is when widgets see the click.
+func (s *Script) Click(frame int, button int, x, y float32) *Script {
+	s.MoveMouse(frame, x, y)
+	s.PressMouse(frame, button)
+	return s.ReleaseMouse(frame+1, button)
+}
+
+// DoubleClick clicks the button twice starting on the given frame. Widgets
+// see the double click on frame+3.
+func (s *Script) DoubleClick(frame int, button int, x, y float32) *Script {
+	s.Click(frame, button, x, y)
+	return s.Click(frame+2, button, x, y)
+}
+
+// Drag presses the button at (x1,y1) on the given frame, moves the mouse to
+// (x2,y2) over the next `steps` frames and then releases the button.
+func (s *Script) Drag(frame int, button int, x1, y1, x2, y2 float32, steps int) *Script {
+	if steps < 1 {
+		steps = 1
+	}
+	s.MoveMouse(frame, x1, y1)
+	s.PressMouse(frame, button)
+	for i := 1; i <= steps; i++ {
+		t := float32(i) / float32(steps)
+		s.MoveMouse(frame+i, x1+(x2-x1)*t, y1+(y2-y1)*t)
+	}
+	return s.ReleaseMouse(frame+steps+1, button)
+}
+
+// Scroll adds the delta to the scroll wheel on the given frame. The delta
+// is multiplied by Manager.ScrollSpeed just like the glfwinput package does.
+func (s *Script) Scroll(frame int, delta float32) *Script {
+	return s.addEvent(event{frame: frame, kind: eventScroll, delta: delta})
+}
+
+// KeyEvent buffers the key press event on the given frame.
+func (s *Script) KeyEvent(frame int, kpe gui.KeyPressEvent) *Script {
+	return s.addEvent(event{frame: frame, kind: eventKey, key: kpe})
+}
+
+// PressKey buffers a non-rune key press (e.g. gui.EweyKeyEnter) on the given frame.
+func (s *Script) PressKey(frame int, keyCode int) *Script {
+	return s.KeyEvent(frame, gui.KeyPressEvent{KeyCode: keyCode})
+}
+
+// HoldKey starts holding down the key (e.g. gui.EweyKeyLeftShift) on the given
+// frame, which the Manager checks with its IsKeyDown function.
+func (s *Script) HoldKey(frame int, keyCode int) *Script {
+	return s.addEvent(event{frame: frame, kind: eventKeyDown, key: gui.KeyPressEvent{KeyCode: keyCode}})
+}
+
+// ReleaseKey stops holding down the key on the given frame.
+func (s *Script) ReleaseKey(frame int, keyCode int) *Script {
+	return s.addEvent(event{frame: frame, kind: eventKeyUp, key: gui.KeyPressEvent{KeyCode: keyCode}})
+}
+
+// TypeRune buffers a rune key press on the given frame.
+func (s *Script) TypeRune(frame int, r rune) *Script {
+	return s.KeyEvent(frame, gui.KeyPressEvent{Rune: r, IsRune: true})
+}
+
+// TypeString buffers a rune key press for every rune in the string on the given frame.
+func (s *Script) TypeString(frame int, msg string) *Script {
+	for _, r := range msg {
+		s.TypeRune(frame, r)
+	}
+	return s
+}
+
+// mouseButtonData is used to track button presses between frames.
+type mouseButtonData struct {
+	// lastPress is the simulated time the last UP->DOWN transition took place
+	lastPress time.Duration
+
+	// lastPressLocation is the position of the mouse when the last UP->DOWN
+	// transition took place
+	lastPressLocation mgl.Vec2
+
+	// lastAction was the last detected action for the button
+	lastAction int
+
+	// doubleClickDetected should be set to true if the last UP->DOWN->UP
+	// sequence was fast enough to be a double click.
+	doubleClickDetected bool
+
+	// lastCheckedFrame is the frame the button action was last checked.
+	// This way, input can be polled only once per frame.
+	lastCheckedFrame int
+}
+
+// SetInputHandlers sets the input callbacks for the GUI Manager to replay
+// the script passed in.
+func SetInputHandlers(uiman *gui.Manager, script *Script) {
+	var mouseX, mouseY float32
+	var lastMouseX, lastMouseY float32
+	buttonsDown := make(map[int]bool)
+	keysDown := make(map[int]bool)
+	scrollWheelDelta := float32(0.0)
+	scrollWheelCache := float32(0.0)
+	var keyBuffer []gui.KeyPressEvent
+
+	// at the start of a new frame, advance the script and apply the events
+	uiman.AddConstructionStartCallback(func(startTime time.Time) {
+		script.frame++
+		lastMouseX, lastMouseY = mouseX, mouseY
+
+		for _, e := range script.events {
+			if e.frame != script.frame {
+				continue
+			}
+
+			switch e.kind {
+			case eventMouseMove:
+				mouseX, mouseY = e.x, e.y
+			case eventMouseDown:
+				buttonsDown[e.button] = true
+			case eventMouseUp:
+				buttonsDown[e.button] = false
+			case eventScroll:
+				scrollWheelDelta += e.delta * uiman.ScrollSpeed
+			case eventKey:
+				keyBuffer = append(keyBuffer, e.key)
+			case eventKeyDown:
+				keysDown[e.key.KeyCode] = true
+			case eventKeyUp:
+				keysDown[e.key.KeyCode] = false
+			}
+		}
+	})
+
+	// simulatedTime returns the time of the current frame in the script
+	simulatedTime := func() time.Duration {
+		return time.Duration(script.frame) * script.FrameDuration
+	}
+
+	uiman.GetMousePosition = func() (float32, float32) {
+		return mouseX, mouseY
+	}
+
+	uiman.GetMousePositionDelta = func() (float32, float32) {
+		return mouseX - lastMouseX, mouseY - lastMouseY
+	}
+
+	const doubleClickThreshold = 500 * time.Millisecond
+	mouseButtonTracker := make(map[int]mouseButtonData)
+
+	uiman.GetMouseButtonAction = func(button int) int {
+		var action int
+		var mbData mouseButtonData
+		var tracked bool
+
+		// get the mouse button data and return the stale result if we're
+		// in the same frame.
+		mbData, tracked = mouseButtonTracker[button]
+		if tracked == true && mbData.lastCheckedFrame == script.frame {
+			return mbData.lastAction
+		}
+
+		// poll the button action
+		if buttonsDown[button] {
+			action = gui.MouseDown
+		} else {
+			action = gui.MouseUp
+		}
+
+		// see if we're tracking this button yet
+		if tracked == false {
+			// create a new mouse button tracker data object
+			if action == gui.MouseDown {
+				mbData.lastPressLocation = mgl.Vec2{mouseX, mouseY}
+				mbData.lastPress = simulatedTime()
+			} else {
+				mbData.lastPress = -doubleClickThreshold
+			}
+		} else {
+			if action == gui.MouseDown {
+				// check to see if there was a transition from UP to DOWN; the
+				// last action could also be a click if the button got pressed
+				// again right after being released.
+				if mbData.lastAction != gui.MouseDown {
+					// a second press within the threshold makes the next
+					// DOWN->UP transition a double click.
+					if simulatedTime()-mbData.lastPress < doubleClickThreshold {
+						mbData.doubleClickDetected = true
+					}
+
+					// count this as a press and log the time
+					mbData.lastPressLocation = mgl.Vec2{mouseX, mouseY}
+					mbData.lastPress = simulatedTime()
+				}
+			} else {
+				// check to see if there was a transition from DOWN to UP
+				if mbData.lastAction == gui.MouseDown {
+					if mbData.doubleClickDetected {
+						// return the double click
+						action = gui.MouseDoubleClick
+
+						// reset the tracker
+						mbData.doubleClickDetected = false
+					} else {
+						// return the single click
+						action = gui.MouseClick
+					}
+				}
+			}
+		}
+
+		// put the updated data back into the map and return the action
+		mbData.lastAction = action
+		mbData.lastCheckedFrame = script.frame
+		mouseButtonTracker[button] = mbData
+		return action
+	}
+
+	uiman.ClearMouseButtonAction = func(buttonNumber int) {
+		mbData, tracked := mouseButtonTracker[buttonNumber]
+		if tracked == true {
+			mbData.lastAction = gui.MouseUp
+			mbData.doubleClickDetected = false
+			mouseButtonTracker[buttonNumber] = mbData
+		}
+	}
+
+	uiman.GetMouseDownPosition = func(button int) (float32, float32) {
+		// is the mouse button down?
+		if uiman.GetMouseButtonAction(button) != gui.MouseUp {
+			mbData, tracked := mouseButtonTracker[button]
+			if tracked == true {
+				return mbData.lastPressLocation[0], mbData.lastPressLocation[1]
+			}
+		}
+
+		// mouse not down or not tracked.
+		return -1.0, -1.0
+	}
+
+	uiman.GetScrollWheelDelta = func(useCached bool) float32 {
+		if useCached {
+			return scrollWheelCache
+		}
+		scrollWheelCache = scrollWheelDelta
+		scrollWheelDelta = 0.0
+		return scrollWheelCache
+	}
+
+	uiman.GetKeyEvents = func() []gui.KeyPressEvent {
+		// hand over the buffer and start a new one so that the caller's
+		// slice doesn't get overwritten by events buffered later.
+		returnVal := keyBuffer
+		keyBuffer = nil
+		return returnVal
+	}
+
+	uiman.ClearKeyEvents = func() {
+		keyBuffer = keyBuffer[:0]
+	}
+
+	uiman.IsKeyDown = func(keyCode int) bool {
+		return keysDown[keyCode]
+	}
+
+	uiman.GetClipboardString = func() (string, error) {
+		return script.Clipboard, nil
+	}
+
+	uiman.SetClipboardString = func(clippy string) {
+		script.Clipboard = clippy
+	}
+
+	uiman.SetCursorShape = func(shape int) {
+		script.CursorShape = shape
+	}
+}
@@ -0,0 +1,574 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package scriptinput
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	gui "github.com/tbogdala/eweygewey"
+	embedded "github.com/tbogdala/eweygewey/embeddedfonts"
+	"github.com/tbogdala/eweygewey/softgfx"
+)
+
+const (
+	testWidth  = 400
+	testHeight = 300
+	testGlyphs = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890., :[]{}\\|<>;\"'~`?/-+_=()*&^%$#@!"
+
+	// the first widget of a window without a title bar at the top-left of
+	// the screen starts at (6,294), so this point is inside of it no matter
+	// how big the font is.
+	firstX = 10
+	firstY = 290
+)
+
+// ctrl returns the key event for pressing ctrl and the rune together.
+func ctrl(r rune) gui.KeyPressEvent {
+	return gui.KeyPressEvent{Rune: r, IsRune: true, CtrlDown: true}
+}
+
+// widgetResults records what the widgets built by a test returned.
+type widgetResults struct {
+	// pressed has the frames that each button reported a press on.
+	pressed map[string][]int
+
+	// changed has the frames the editbox or the combo box reported a
+	// change on.
+	changed []int
+
+	// text is the value of the editbox.
+	text string
+
+	// value is the value of the drag slider.
+	value int
+
+	// selected is the index picked in the combo box.
+	selected int
+
+	// ranges has the rows the list clipper built on each frame.
+	ranges [][2]int
+}
+
+func TestScript(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// text is the starting value of the editbox
+		text string
+
+		// titleBar shows the title bar of the window
+		titleBar bool
+
+		// build creates the widgets of the window each frame
+		build func(wnd *gui.Window, s *Script, r *widgetResults)
+
+		// script schedules the input
+		script func(s *Script)
+
+		// frames is the number of frames to construct
+		frames int
+
+		// check tests the results after the last frame
+		check func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults)
+	}{
+		{
+			// the button sees the click on the frame the mouse button gets
+			// released, but only if it was pressed inside of the button
+			name: "click",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				buildButton(wnd, s, r, "a")
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.Drag(40, 0, 200, 100, firstX, firstY, 2)
+			},
+			frames: 50,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if want := []int{3}; !reflect.DeepEqual(r.pressed["a"], want) {
+					t.Errorf("The button was pressed on frames %v instead of %v.", r.pressed["a"], want)
+				}
+			},
+		},
+		{
+			// two clicks in a row select the word under the mouse
+			name:  "double-click",
+			text:  "hello world",
+			build: buildEditbox,
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.DoubleClick(2, 0, firstX, firstY)
+				s.KeyEvent(10, ctrl('C'))
+			},
+			frames: 12,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if s.Clipboard != "hello" {
+					t.Errorf("The double click selected %q instead of the first word.", s.Clipboard)
+				}
+			},
+		},
+		{
+			// the mouse delta of each frame of the drag moves the slider
+			name: "drag",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				wnd.DragSliderInt("drag", 1.0, &r.value)
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Drag(2, 0, firstX, firstY, firstX+40, firstY, 4)
+			},
+			frames: 10,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if r.value != 40 {
+					t.Errorf("The drag slider is %d instead of 40.", r.value)
+				}
+			},
+		},
+		{
+			// the window gets dragged by its title bar
+			name:     "drag window",
+			titleBar: true,
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				wnd.Text("dragged")
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY+4)
+				s.Drag(2, 0, firstX, firstY+4, firstX+40, firstY-36, 4)
+			},
+			frames: 10,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				x, y, _, _ := wnd.GetFrameSize()
+				if math.Abs(float64(x-40)) > 0.01 || math.Abs(float64(y-(testHeight-40))) > 0.01 {
+					t.Errorf("The window is at (%v,%v) instead of (40,%v).", x, y, testHeight-40)
+				}
+			},
+		},
+		{
+			// holding shift while using the scroll wheel scrolls sideways
+			name: "key hold",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				wnd.Custom(2.0, 2.0, wnd.Style.WindowPadding, func() {})
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, 200, 150)
+				s.HoldKey(2, gui.EweyKeyLeftShift)
+				s.Scroll(3, -1)
+				s.ReleaseKey(4, gui.EweyKeyLeftShift)
+			},
+			frames: 6,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if wnd.ScrollOffsetX <= 0 || wnd.ScrollOffset != 0 {
+					t.Errorf("The window scrolled to (%v,%v) instead of only sideways.", wnd.ScrollOffsetX, wnd.ScrollOffset)
+				}
+			},
+		},
+		{
+			// the clipboard is shared by copy, cut and paste
+			name:  "clipboard",
+			text:  "hello",
+			build: buildEditbox,
+			script: func(s *Script) {
+				s.Clipboard = " world"
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.PressKey(5, gui.EweyKeyEnd)
+				s.KeyEvent(6, ctrl('V'))
+				s.KeyEvent(7, ctrl('A'))
+				s.KeyEvent(8, ctrl('X'))
+			},
+			frames: 10,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if s.Clipboard != "hello world" || r.text != "" {
+					t.Errorf("The clipboard is %q and the editbox is %q after cutting.", s.Clipboard, r.text)
+				}
+				if want := []int{6, 8}; !reflect.DeepEqual(r.changed, want) {
+					t.Errorf("The editbox changed on frames %v instead of %v.", r.changed, want)
+				}
+			},
+		},
+		{
+			// Tab moves the focus to the next widget and Enter presses it
+			name: "focus",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				buildButton(wnd, s, r, "a")
+				buildButton(wnd, s, r, "b")
+			},
+			script: func(s *Script) {
+				s.PressKey(2, gui.EweyKeyTab)
+				s.PressKey(3, gui.EweyKeyTab)
+				s.PressKey(4, gui.EweyKeyEnter)
+			},
+			frames: 6,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if ui.GetFocusedID() != "b" {
+					t.Errorf("The focus is on %q instead of the second button.", ui.GetFocusedID())
+				}
+				if len(r.pressed["a"]) != 0 || !reflect.DeepEqual(r.pressed["b"], []int{4}) {
+					t.Errorf("The buttons were pressed on frames %v.", r.pressed)
+				}
+			},
+		},
+		{
+			// typing changes the value and Enter is reported too
+			name:  "editbox",
+			text:  "hello",
+			build: buildEditbox,
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.PressKey(5, gui.EweyKeyEnd)
+				s.TypeString(6, " you")
+				s.PressKey(7, gui.EweyKeyBackspace)
+				s.PressKey(8, gui.EweyKeyEnter)
+			},
+			frames: 10,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if r.text != "hello yo" {
+					t.Errorf("The editbox is %q instead of \"hello yo\".", r.text)
+				}
+				if want := []int{6, 7, 8}; !reflect.DeepEqual(r.changed, want) {
+					t.Errorf("The editbox returned true on frames %v instead of %v.", r.changed, want)
+				}
+			},
+		},
+		{
+			// runes typed in a row are undone together
+			name:  "undo",
+			text:  "hello",
+			build: buildEditbox,
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.PressKey(5, gui.EweyKeyEnd)
+				s.TypeString(6, " there")
+				s.TypeString(7, " you")
+				s.KeyEvent(8, ctrl('A'))
+				s.PressKey(9, gui.EweyKeyDelete)
+				s.KeyEvent(10, ctrl('Z'))
+				s.KeyEvent(11, ctrl('Z'))
+				s.KeyEvent(12, ctrl('Y'))
+			},
+			frames: 14,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if r.text != "hello there you" {
+					t.Errorf("The editbox is %q after undo and redo.", r.text)
+				}
+			},
+		},
+		{
+			// a password can't be copied and cutting it only deletes it
+			name: "password",
+			text: "secret",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				wnd.EditboxAdv("edit", &r.text, gui.EditboxOptions{Password: true})
+			},
+			script: func(s *Script) {
+				s.Clipboard = "clipboard"
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.KeyEvent(5, ctrl('A'))
+				s.KeyEvent(6, ctrl('C'))
+				s.KeyEvent(7, ctrl('X'))
+			},
+			frames: 10,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if s.Clipboard != "clipboard" || r.text != "" {
+					t.Errorf("The clipboard is %q and the editbox is %q after cutting.", s.Clipboard, r.text)
+				}
+			},
+		},
+		{
+			// the integer filter drops the runes that aren't digits or signs
+			name: "int filter",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				wnd.EditboxAdv("edit", &r.text, gui.EditboxOptions{Filter: gui.EditboxFilterInt})
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.TypeString(5, "-1a2.3٣")
+			},
+			frames: 8,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if r.text != "-123" {
+					t.Errorf("The editbox is %q instead of \"-123\".", r.text)
+				}
+			},
+		},
+		{
+			// clicking the box opens the list, Enter picks the highlighted
+			// item and Escape closes the list without picking one; with the
+			// list closed, Down changes the selection directly
+			name: "combo",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				buildCombo(wnd, s, r, comboItems)
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.PressKey(5, gui.EweyKeyDown)
+				s.PressKey(6, gui.EweyKeyEnter)
+				s.Click(40, 0, firstX, firstY)
+				s.PressKey(43, gui.EweyKeyDown)
+				s.PressKey(44, gui.EweyKeyEscape)
+				s.PressKey(46, gui.EweyKeyDown)
+			},
+			frames: 50,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if r.selected != 2 {
+					t.Errorf("The combo box selected %d instead of 2.", r.selected)
+				}
+				if want := []int{6, 46}; !reflect.DeepEqual(r.changed, want) {
+					t.Errorf("The combo box changed on frames %v instead of %v.", r.changed, want)
+				}
+			},
+		},
+		{
+			// the list closes when the items go away while it's open
+			name: "combo emptied",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				items := comboItems
+				if s.Frame() >= 5 && s.Frame() < 10 {
+					items = nil
+				}
+				buildCombo(wnd, s, r, items)
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Click(2, 0, firstX, firstY)
+				s.PressKey(12, gui.EweyKeyDown)
+			},
+			frames: 15,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if want := []int{12}; r.selected != 1 || !reflect.DeepEqual(r.changed, want) {
+					t.Errorf("The combo box selected %d on frames %v instead of 1 on %v.", r.selected, r.changed, want)
+				}
+			},
+		},
+		{
+			// dragging the border between the columns resizes the first
+			// column, which moves the button in the second one
+			name: "table border",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				columns := []gui.TableColumn{{Label: "a", Sizing: gui.TableColumnFixed, Width: 0.25}, {Label: "b"}}
+				wnd.BeginTableAdv("table", columns, gui.TableOptions{Resizable: true})
+				wnd.TableNextRow()
+				wnd.TableNextColumn()
+				buildButton(wnd, s, r, "first")
+				wnd.TableNextColumn()
+				buildButton(wnd, s, r, "second")
+				wnd.EndTable()
+			},
+			script: func(s *Script) {
+				// the border starts a quarter of the window past the padding
+				// and the buttons start below the padding of the cells
+				border, y := float32(4+testWidth/4), float32(firstY-10)
+				s.MoveMouse(1, border+112, y)
+				s.Click(2, 0, border+112, y)
+				s.MoveMouse(39, border, y)
+				s.Drag(40, 0, border, y, border+100, y, 4)
+				s.MoveMouse(79, border+112, y)
+				s.Click(80, 0, border+112, y)
+			},
+			frames: 85,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				if want := []int{81}; len(r.pressed["first"]) != 0 || !reflect.DeepEqual(r.pressed["second"], want) {
+					t.Errorf("The buttons were pressed on frames %v instead of the second on %v.", r.pressed, want)
+				}
+			},
+		},
+		{
+			// the clipper only builds the rows in view at the top and at the
+			// bottom of the scroll range
+			name: "list clipper",
+			build: func(wnd *gui.Window, s *Script, r *widgetResults) {
+				clipper := wnd.BeginListClipper(1000, 0.1)
+				for i := clipper.DisplayStart; i < clipper.DisplayEnd; i++ {
+					wnd.StartRow()
+					wnd.Custom(0.5, 0.1, mgl.Vec4{}, func() {})
+				}
+				clipper.End()
+				r.ranges = append(r.ranges, [2]int{clipper.DisplayStart, clipper.DisplayEnd})
+			},
+			script: func(s *Script) {
+				s.MoveMouse(1, firstX, firstY)
+				s.Scroll(10, -1000000)
+			},
+			frames: 15,
+			check: func(t *testing.T, ui *gui.Manager, wnd *gui.Window, s *Script, r *widgetResults) {
+				// 300 pixels of the window show ten rows of 30 pixels
+				if want := [2]int{0, 10}; r.ranges[5] != want {
+					t.Errorf("The clipper built the rows %v at the top instead of %v.", r.ranges[5], want)
+				}
+				if want := [2]int{990, 1000}; r.ranges[14] != want {
+					t.Errorf("The clipper built the rows %v at the bottom instead of %v.", r.ranges[14], want)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ui, s := newTestManager(t)
+			r := &widgetResults{pressed: make(map[string][]int), text: test.text}
+			wnd := ui.NewWindow("test", 0, 1, 1, 1, func(wnd *gui.Window) {
+				test.build(wnd, s, r)
+			})
+			wnd.ShowTitleBar = test.titleBar
+			wnd.IsScrollable = true
+
+			test.script(s)
+			runFrames(ui, test.frames)
+			test.check(t, ui, wnd, s, r)
+		})
+	}
+}
+
+// TestScriptTabBar closes a tab with its close button and then drags a tab
+// past its neighbor to swap them.
+func TestScriptTabBar(t *testing.T) {
+	ui, s := newTestManager(t)
+	labels := []string{"one", "two", "three"}
+	selected := 0
+	wnd := ui.NewWindow("test", 0, 1, 1, 1, func(wnd *gui.Window) {
+		selected, _ = wnd.TabBarAdv("tabs", &labels, gui.TabBarOptions{Closable: true, Reorderable: true})
+	})
+	wnd.ShowTitleBar = false
+
+	// measure the tabs the same way the tab bar does; the bar starts where
+	// the first widget does at (6,294)
+	style := gui.DefaultStyle
+	font := ui.GetFont(style.FontName)
+	_, dimY, _ := font.GetRenderSize("0.0")
+	tabH := dimY + style.TabPadding[2] + style.TabPadding[3]
+	closeSize := tabH * 0.5
+	tabW := func(label string) float32 {
+		dimX, _, _ := font.GetRenderSize(label)
+		return dimX + style.TabPadding[0] + style.TabPadding[1] + closeSize + style.TabPadding[0]
+	}
+	left, top := float32(6), float32(294)
+	midY := top - tabH*0.5
+
+	// the close button of "two" is at the right side of the second tab
+	closeX := left + tabW("one") + style.TabSpacing + tabW("two") - style.TabPadding[1] - closeSize*0.5
+	s.MoveMouse(1, closeX, midY)
+	s.Click(2, 0, closeX, midY)
+
+	// dragging "one" past the end of "three" swaps them
+	startX := left + tabW("one")*0.5
+	endX := left + tabW("one") + style.TabSpacing + tabW("three") + 4
+	s.Drag(40, 0, startX, midY, endX, midY, 4)
+
+	runFrames(ui, 50)
+
+	if want := []string{"three", "one"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("The tabs are %v instead of %v.", labels, want)
+	}
+	if selected != 1 {
+		t.Errorf("The tab bar selected %d instead of the dragged tab.", selected)
+	}
+}
+
+// TestScriptDocking docks a window by dropping its title bar on the right
+// drop target of another window and then undocks it by dragging it away,
+// which puts back the size it had while floating.
+func TestScriptDocking(t *testing.T) {
+	ui, s := newTestManager(t)
+	target := ui.NewWindow("target", 0, 1, 0.5, 1, func(wnd *gui.Window) {
+		wnd.Text("target")
+	})
+	target.ShowTitleBar = false
+	dragged := ui.NewWindow("dragged", 0.55, 0.8, 0.4, 0.5, func(wnd *gui.Window) {
+		wnd.Text("dragged")
+	})
+
+	// the title bar of the dragged window starts at (220,240) and the drop
+	// target for the right side is next to the one in the center of the
+	// target window, which covers the left half of the screen
+	size := ui.DockStyle.DockTargetSize
+	rightX, rightY := float32(testWidth/4)+size*1.25, float32(testHeight/2)
+	s.MoveMouse(1, 230, 237)
+	s.Drag(2, 0, 230, 237, rightX, rightY, 10)
+
+	// dragging the title bar of the docked window at the top of the screen
+	// undocks it and it's dropped away from all of the drop targets
+	s.MoveMouse(39, 150, 297)
+	s.Drag(40, 0, 150, 297, 300, 100, 10)
+
+	runFrames(ui, 20)
+	if dragged.GetDockNode() == nil || target.GetDockNode() == nil {
+		t.Fatalf("The window wasn't docked next to the target.")
+	}
+	dockedX, _, dockedW, _ := dragged.GetFrameSize()
+	if dockedX < testWidth/4 || dockedX+dockedW > testWidth/2+0.01 {
+		t.Errorf("The docked window is %v wide at %v instead of in the right half of the target.", dockedW, dockedX)
+	}
+
+	runFrames(ui, 40)
+	if dragged.GetDockNode() != nil {
+		t.Fatalf("The window is still docked after dragging it away.")
+	}
+	if dragged.Width != 0.4 || dragged.Height != 0.5 {
+		t.Errorf("The undocked window is %vx%v instead of 0.4x0.5.", dragged.Width, dragged.Height)
+	}
+}
+
+// newTestManager creates a Manager drawn by softgfx that gets its input from
+// a new Script.
+func newTestManager(t *testing.T) (*gui.Manager, *Script) {
+	gfx := softgfx.NewGraphicsImpl(testWidth, testHeight)
+	ui := gui.NewManager(gfx)
+	if err := ui.Initialize(gui.VertShader330, gui.FragShader330, testWidth, testHeight, testHeight); err != nil {
+		t.Fatalf("Failed to initialize the Manager: %v", err)
+	}
+
+	fontBytes, err := embedded.OswaldHeavyTtfBytes()
+	if err != nil {
+		t.Fatalf("Failed to load the embedded font: %v", err)
+	}
+	if _, err := ui.NewFontBytes("Default", fontBytes, 14, testGlyphs); err != nil {
+		t.Fatalf("Failed to create the font: %v", err)
+	}
+
+	s := NewScript()
+	SetInputHandlers(ui, s)
+	return ui, s
+}
+
+// runFrames constructs and draws the given number of frames.
+func runFrames(ui *gui.Manager, frames int) {
+	for i := 0; i < frames; i++ {
+		ui.Construct(1.0 / 60.0)
+		ui.Draw()
+	}
+}
+
+// buildButton builds a button and records the frames it was pressed on.
+func buildButton(wnd *gui.Window, s *Script, r *widgetResults, id string) {
+	if pressed, _ := wnd.Button(id, id); pressed {
+		r.pressed[id] = append(r.pressed[id], s.Frame())
+	}
+}
+
+// buildEditbox builds an editbox for the text and records the frames it
+// returned true on.
+func buildEditbox(wnd *gui.Window, s *Script, r *widgetResults) {
+	if changed, _ := wnd.Editbox("edit", &r.text); changed {
+		r.changed = append(r.changed, s.Frame())
+	}
+}
+
+// comboItems are the items of the combo box built by buildCombo.
+var comboItems = []string{"one", "two", "three"}
+
+// buildCombo builds a combo box of the items and records the frames it
+// returned true on.
+func buildCombo(wnd *gui.Window, s *Script, r *widgetResults, items []string) {
+	if changed, _ := wnd.Combo("combo", &r.selected, items); changed {
+		r.changed = append(r.changed, s.Frame())
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package softgfx
+
+import (
+	"encoding/binary"
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	graphics "github.com/tbogdala/fizzle/graphicsprovider"
+)
+
+// vertex is a single vertex after it has been fetched from the buffers and
+// transformed into window coordinates.
+type vertex struct {
+	x, y         float32 // window coordinates with the origin in the lower left
+	u, v         float32
+	textureIndex float32
+	color        mgl.Vec4
+}
+
+// readAttrib reads up to four floats for the attribute at the vertex index.
+// Attributes that are disabled or out of range return the OpenGL default of (0,0,0,1).
+func (gfx *GraphicsImpl) readAttrib(location int, index int) [4]float32 {
+	result := [4]float32{0, 0, 0, 1}
+	attr := gfx.attribs[location]
+	if !attr.enabled {
+		return result
+	}
+
+	buf := gfx.buffers[attr.buffer]
+	start := attr.offset + index*attr.stride
+	for i := 0; i < attr.size && i < 4; i++ {
+		byteOffset := start + i*4
+		if byteOffset < 0 || byteOffset+4 > len(buf) {
+			gfx.setError(errInvalidOperation)
+			break
+		}
+		result[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[byteOffset:]))
+	}
+
+	return result
+}
+
+// fetchVertex runs the emulated vertex shader for the vertex index which
+// transforms the position by the VIEW matrix and then maps it to the viewport.
+func (gfx *GraphicsImpl) fetchVertex(index int) vertex {
+	var vert vertex
+
+	pos := gfx.readAttrib(attribPosition, index)
+	uv := gfx.readAttrib(attribUV, index)
+	texIdx := gfx.readAttrib(attribTextureIndex, index)
+	color := gfx.readAttrib(attribColor, index)
+
+	clip := gfx.view.Mul4x1(mgl.Vec4{pos[0], pos[1], 0.0, 1.0})
+	if clip[3] != 0.0 {
+		clip[0] /= clip[3]
+		clip[1] /= clip[3]
+	}
+
+	// snap the window coordinates to a sub-pixel grid like GPUs do so that
+	// floating point error doesn't push edges off of pixel centers.
+	vp := gfx.viewport
+	vert.x = snapSubPixel(float32(vp[0]) + (clip[0]+1.0)*0.5*float32(vp[2]))
+	vert.y = snapSubPixel(float32(vp[1]) + (clip[1]+1.0)*0.5*float32(vp[3]))
+	vert.u = uv[0]
+	vert.v = uv[1]
+	vert.textureIndex = texIdx[0]
+	vert.color = mgl.Vec4{color[0], color[1], color[2], color[3]}
+
+	return vert
+}
+
+// rasterBounds returns the pixel rectangle [min,max) that fragments can be
+// written to based on the viewport, framebuffer and possibly scissor box.
+func (gfx *GraphicsImpl) rasterBounds(useViewport bool) (int, int, int, int) {
+	minX, minY := 0, 0
+	maxX, maxY := gfx.Framebuffer.Rect.Dx(), gfx.Framebuffer.Rect.Dy()
+
+	clampTo := func(x, y, w, h int32) {
+		if int(x) > minX {
+			minX = int(x)
+		}
+		if int(y) > minY {
+			minY = int(y)
+		}
+		if int(x+w) < maxX {
+			maxX = int(x + w)
+		}
+		if int(y+h) < maxY {
+			maxY = int(y + h)
+		}
+	}
+
+	if useViewport {
+		vp := gfx.viewport
+		clampTo(vp[0], vp[1], vp[2], vp[3])
+	}
+	if gfx.scissorTest {
+		sb := gfx.scissorBox
+		clampTo(sb[0], sb[1], sb[2], sb[3])
+	}
+
+	return minX, minY, maxX, maxY
+}
+
+// edgeFunction returns twice the signed area of the triangle (a, b, p); it is
+// positive when p is to the left of the directed edge a->b.
+func edgeFunction(ax, ay, bx, by, px, py float32) float32 {
+	return (bx-ax)*(py-ay) - (by-ay)*(px-ax)
+}
+
+// isTopLeft returns true if the directed edge a->b of a counter-clockwise
+// triangle is a top or left edge. Pixels whose centers fall exactly on these
+// edges get drawn so that shared edges are not drawn twice.
+func isTopLeft(ax, ay, bx, by float32) bool {
+	dx := bx - ax
+	dy := by - ay
+	return dy < 0 || (dy == 0 && dx < 0)
+}
+
+// rasterizeTriangle fills the pixels covered by the triangle and runs the
+// emulated fragment shader for each one.
+func (gfx *GraphicsImpl) rasterizeTriangle(tri [3]vertex) {
+	area := edgeFunction(tri[0].x, tri[0].y, tri[1].x, tri[1].y, tri[2].x, tri[2].y)
+	if area == 0 {
+		return
+	}
+
+	// make the winding counter-clockwise so the edge tests are consistent
+	if area < 0 {
+		tri[1], tri[2] = tri[2], tri[1]
+		area = -area
+	}
+	v0, v1, v2 := tri[0], tri[1], tri[2]
+
+	// find the bounding box of the triangle clipped to the drawable area
+	minX, minY, maxX, maxY := gfx.rasterBounds(true)
+	bbMinX := int(math.Floor(float64(min3(v0.x, v1.x, v2.x))))
+	bbMinY := int(math.Floor(float64(min3(v0.y, v1.y, v2.y))))
+	bbMaxX := int(math.Ceil(float64(max3(v0.x, v1.x, v2.x))))
+	bbMaxY := int(math.Ceil(float64(max3(v0.y, v1.y, v2.y))))
+	if bbMinX > minX {
+		minX = bbMinX
+	}
+	if bbMinY > minY {
+		minY = bbMinY
+	}
+	if bbMaxX < maxX {
+		maxX = bbMaxX
+	}
+	if bbMaxY < maxY {
+		maxY = bbMaxY
+	}
+
+	topLeft0 := isTopLeft(v1.x, v1.y, v2.x, v2.y)
+	topLeft1 := isTopLeft(v2.x, v2.y, v0.x, v0.y)
+	topLeft2 := isTopLeft(v0.x, v0.y, v1.x, v1.y)
+
+	fbHeight := gfx.Framebuffer.Rect.Dy()
+	for py := minY; py < maxY; py++ {
+		cy := float32(py) + 0.5
+		for px := minX; px < maxX; px++ {
+			cx := float32(px) + 0.5
+
+			w0 := edgeFunction(v1.x, v1.y, v2.x, v2.y, cx, cy)
+			w1 := edgeFunction(v2.x, v2.y, v0.x, v0.y, cx, cy)
+			w2 := edgeFunction(v0.x, v0.y, v1.x, v1.y, cx, cy)
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				continue
+			}
+			if (w0 == 0 && !topLeft0) || (w1 == 0 && !topLeft1) || (w2 == 0 && !topLeft2) {
+				continue
+			}
+
+			// interpolate the vertex attributes with barycentric weights
+			b0, b1, b2 := w0/area, w1/area, w2/area
+			u := b0*v0.u + b1*v1.u + b2*v2.u
+			v := b0*v0.v + b1*v1.v + b2*v2.v
+			c := v0.color.Mul(b0).Add(v1.color.Mul(b1)).Add(v2.color.Mul(b2))
+
+			// the texture index is constant across a face in practice, so
+			// just use the provoking vertex like the flat shaded value would.
+			texel := gfx.sample(int(v0.textureIndex), u, v)
+			frag := mgl.Vec4{c[0] * texel[0], c[1] * texel[1], c[2] * texel[2], c[3] * texel[3]}
+
+			gfx.writeFragment(px, fbHeight-1-py, frag)
+		}
+	}
+}
+
+// writeFragment stores the fragment color in the framebuffer, blending it
+// with the existing color if blending is enabled.
+func (gfx *GraphicsImpl) writeFragment(x, y int, frag mgl.Vec4) {
+	i := gfx.Framebuffer.PixOffset(x, y)
+	pix := gfx.Framebuffer.Pix[i : i+4 : i+4]
+
+	if gfx.blend {
+		srcA := clampUnit(frag[3])
+		for ch := 0; ch < 4; ch++ {
+			dst := float32(pix[ch]) / 255.0
+			pix[ch] = floatToByte(clampUnit(frag[ch])*srcA + dst*(1.0-srcA))
+		}
+		return
+	}
+
+	for ch := 0; ch < 4; ch++ {
+		pix[ch] = floatToByte(frag[ch])
+	}
+}
+
+// sample looks up the texel for the (u,v) coordinate in the texture that the
+// TEX[samplerIndex] sampler points to. Missing textures sample as opaque black
+// like incomplete textures do in OpenGL.
+func (gfx *GraphicsImpl) sample(samplerIndex int, u, v float32) mgl.Vec4 {
+	if samplerIndex < 0 || samplerIndex >= maxSamplers {
+		return mgl.Vec4{0, 0, 0, 1}
+	}
+	tex := gfx.boundTexture(int(gfx.samplers[samplerIndex]))
+	if tex == nil || tex.width == 0 || tex.height == 0 {
+		return mgl.Vec4{0, 0, 0, 1}
+	}
+
+	x := u*float32(tex.width) - 0.5
+	y := v*float32(tex.height) - 0.5
+
+	if tex.magFilter != graphics.LINEAR {
+		return tex.texel(int(math.Floor(float64(x+0.5))), int(math.Floor(float64(y+0.5))))
+	}
+
+	// bilinear filtering
+	x0 := int(math.Floor(float64(x)))
+	y0 := int(math.Floor(float64(y)))
+	fx := x - float32(x0)
+	fy := y - float32(y0)
+	t00 := tex.texel(x0, y0)
+	t10 := tex.texel(x0+1, y0)
+	t01 := tex.texel(x0, y0+1)
+	t11 := tex.texel(x0+1, y0+1)
+	top := t00.Mul(1.0 - fx).Add(t10.Mul(fx))
+	bottom := t01.Mul(1.0 - fx).Add(t11.Mul(fx))
+	return top.Mul(1.0 - fy).Add(bottom.Mul(fy))
+}
+
+// texel returns the color at the texel coordinate after applying the wrap modes.
+func (tex *texture) texel(x, y int) mgl.Vec4 {
+	x = wrapCoord(x, tex.width, tex.wrapS)
+	y = wrapCoord(y, tex.height, tex.wrapT)
+	i := (y*tex.width + x) * 4
+	return mgl.Vec4{
+		float32(tex.pix[i]) / 255.0,
+		float32(tex.pix[i+1]) / 255.0,
+		float32(tex.pix[i+2]) / 255.0,
+		float32(tex.pix[i+3]) / 255.0,
+	}
+}
+
+// wrapCoord maps a texel coordinate into [0 .. size) using either the
+// REPEAT or the CLAMP_TO_EDGE wrap mode.
+func wrapCoord(c, size int, mode int32) int {
+	if mode == graphics.REPEAT {
+		c %= size
+		if c < 0 {
+			c += size
+		}
+		return c
+	}
+
+	if c < 0 {
+		return 0
+	}
+	if c >= size {
+		return size - 1
+	}
+	return c
+}
+
+// snapSubPixel rounds the coordinate to 8 bits of sub-pixel precision.
+func snapSubPixel(f float32) float32 {
+	const subPixelSteps = 256.0
+	return float32(math.Floor(float64(f)*subPixelSteps+0.5) / subPixelSteps)
+}
+
+// clampUnit clamps the value to [0 .. 1].
+func clampUnit(f float32) float32 {
+	if f < 0.0 {
+		return 0.0
+	}
+	if f > 1.0 {
+		return 1.0
+	}
+	return f
+}
+
+func min3(a, b, c float32) float32 {
+	return float32(math.Min(float64(a), math.Min(float64(b), float64(c))))
+}
+
+func max3(a, b, c float32) float32 {
+	return float32(math.Max(float64(a), math.Max(float64(b), float64(c))))
+}
@@ -0,0 +1,673 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package softgfx
+
+/*
+Package softgfx provides a pure Go implementation of the fizzle GraphicsProvider
+interface that rasterizes into an image.RGBA instead of talking to a GPU.
+
+It does not execute GLSL. Instead it emulates the single shader program used
+by eweygewey (VertShader330 / FragShader330): the VERTEX_POSITION, VERTEX_UV,
+VERTEX_TEXTURE_INDEX and VERTEX_COLOR attributes are read from the bound
+buffers, transformed by the VIEW matrix and the fragment color is the vertex
+color multiplied by the texel looked up in the TEX[n] sampler.
+
+This makes it possible to construct and draw a Manager on machines without
+a GPU and then compare the resulting image against a known good one.
+*/
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	graphics "github.com/tbogdala/fizzle/graphicsprovider"
+)
+
+// the attribute locations handed out for the eweygewey shader inputs
+const (
+	attribPosition     = 0
+	attribUV           = 1
+	attribTextureIndex = 2
+	attribColor        = 3
+	attribCount        = 4
+)
+
+// the uniform locations handed out for the eweygewey shader uniforms; the
+// samplers in the TEX[] array start at uniformSamplerBase.
+const (
+	uniformView        = 0
+	uniformSamplerBase = 1
+	maxSamplers        = 16
+)
+
+// OpenGL error codes reported through GetError().
+const (
+	errInvalidEnum      = 0x0500
+	errInvalidValue     = 0x0501
+	errInvalidOperation = 0x0502
+)
+
+// texture is the in-memory storage for a texture object.
+type texture struct {
+	width, height int
+	pix           []byte // RGBA8 data; the first row is t=0
+	magFilter     int32
+	wrapS, wrapT  int32
+}
+
+// vertexAttrib is the state recorded by VertexAttribPointer().
+type vertexAttrib struct {
+	enabled bool
+	buffer  graphics.Buffer
+	size    int
+	stride  int
+	offset  int
+}
+
+// GraphicsImpl is a software rasterizer that implements the fizzle
+// GraphicsProvider interface well enough to draw the user interface.
+type GraphicsImpl struct {
+	// Framebuffer is the image that draw calls get rasterized into. Unlike
+	// OpenGL, the origin of the image is the top-left corner.
+	Framebuffer *image.RGBA
+
+	clearColor  [4]float32
+	viewport    [4]int32
+	scissorBox  [4]int32
+	scissorTest bool
+	blend       bool
+	lastError   uint32
+	nextName    uint32
+
+	textures     map[graphics.Texture]*texture
+	buffers      map[graphics.Buffer][]byte
+	boundBuffers map[graphics.Enum]graphics.Buffer
+	activeUnit   int
+	textureUnits map[int]graphics.Texture
+	attribs      [attribCount]vertexAttrib
+	samplers     [maxSamplers]int32
+	view         mgl.Mat4
+}
+
+// NewGraphicsImpl creates a new software graphics provider that renders
+// into a framebuffer of the given size in pixels.
+func NewGraphicsImpl(width, height int) *GraphicsImpl {
+	gfx := new(GraphicsImpl)
+	gfx.Framebuffer = image.NewRGBA(image.Rect(0, 0, width, height))
+	gfx.viewport = [4]int32{0, 0, int32(width), int32(height)}
+	gfx.scissorBox = gfx.viewport
+	gfx.textures = make(map[graphics.Texture]*texture)
+	gfx.buffers = make(map[graphics.Buffer][]byte)
+	gfx.boundBuffers = make(map[graphics.Enum]graphics.Buffer)
+	gfx.textureUnits = make(map[int]graphics.Texture)
+	gfx.view = mgl.Ident4()
+
+	// by default each sampler reads from the texture unit of the same number
+	for i := range gfx.samplers {
+		gfx.samplers[i] = int32(i)
+	}
+
+	return gfx
+}
+
+// Image returns the framebuffer that has been rendered to.
+func (gfx *GraphicsImpl) Image() *image.RGBA {
+	return gfx.Framebuffer
+}
+
+// SavePNG writes the framebuffer out to a PNG file.
+func (gfx *GraphicsImpl) SavePNG(filepath string) error {
+	f, err := os.Create(filepath)
+	if err != nil {
+		return fmt.Errorf("Failed to create the PNG file.\n%v", err)
+	}
+	defer f.Close()
+
+	err = png.Encode(f, gfx.Framebuffer)
+	if err != nil {
+		return fmt.Errorf("Failed to encode the framebuffer as a PNG.\n%v", err)
+	}
+
+	return nil
+}
+
+// genName returns a new non-zero object name.
+func (gfx *GraphicsImpl) genName() uint32 {
+	gfx.nextName++
+	return gfx.nextName
+}
+
+// setError records an error code if one hasn't been recorded already.
+func (gfx *GraphicsImpl) setError(code uint32) {
+	if gfx.lastError == graphics.NO_ERROR {
+		gfx.lastError = code
+	}
+}
+
+// boundTexture returns the texture bound to the given texture unit or nil.
+func (gfx *GraphicsImpl) boundTexture(unit int) *texture {
+	name, okay := gfx.textureUnits[unit]
+	if !okay {
+		return nil
+	}
+	return gfx.textures[name]
+}
+
+// ActiveTexture selects the active texture unit.
+func (gfx *GraphicsImpl) ActiveTexture(t graphics.Texture) {
+	gfx.activeUnit = int(t - graphics.TEXTURE0)
+}
+
+// AttachShader is a no-op for the software renderer.
+func (gfx *GraphicsImpl) AttachShader(p graphics.Program, s graphics.Shader) {}
+
+// BindBuffer binds a buffer object to the target.
+func (gfx *GraphicsImpl) BindBuffer(target graphics.Enum, b graphics.Buffer) {
+	gfx.boundBuffers[target] = b
+}
+
+// BindFragDataLocation is a no-op for the software renderer.
+func (gfx *GraphicsImpl) BindFragDataLocation(p graphics.Program, color uint32, name string) {}
+
+// BindFramebuffer is a no-op for the software renderer; there is only the one framebuffer.
+func (gfx *GraphicsImpl) BindFramebuffer(target graphics.Enum, fb graphics.Buffer) {}
+
+// BindRenderbuffer is a no-op for the software renderer.
+func (gfx *GraphicsImpl) BindRenderbuffer(target graphics.Enum, renderbuffer graphics.Buffer) {}
+
+// BindTexture binds a texture to the active texture unit.
+func (gfx *GraphicsImpl) BindTexture(target graphics.Enum, t graphics.Texture) {
+	if target != graphics.TEXTURE_2D {
+		gfx.setError(errInvalidEnum)
+		return
+	}
+	gfx.textureUnits[gfx.activeUnit] = t
+	if _, okay := gfx.textures[t]; !okay && t != 0 {
+		gfx.textures[t] = &texture{magFilter: graphics.LINEAR, wrapS: graphics.REPEAT, wrapT: graphics.REPEAT}
+	}
+}
+
+// BindVertexArray is a no-op for the software renderer; attribute state is global.
+func (gfx *GraphicsImpl) BindVertexArray(a uint32) {}
+
+// BlendEquation is a no-op; the blend equation is always FUNC_ADD.
+func (gfx *GraphicsImpl) BlendEquation(mode graphics.Enum) {}
+
+// BlendFunc is a no-op; blending always uses SRC_ALPHA, ONE_MINUS_SRC_ALPHA.
+func (gfx *GraphicsImpl) BlendFunc(sFactor, dFactor graphics.Enum) {}
+
+// BlitFramebuffer is a no-op for the software renderer.
+func (gfx *GraphicsImpl) BlitFramebuffer(srcX0, srcY0, srcX1, srcY1, dstX0, dstY0, dstX1, dstY1 int32, mask graphics.Bitfield, filter graphics.Enum) {
+}
+
+// BufferData copies size bytes from data into the buffer bound to target.
+func (gfx *GraphicsImpl) BufferData(target graphics.Enum, size int, data unsafe.Pointer, usage graphics.Enum) {
+	name, okay := gfx.boundBuffers[target]
+	if !okay || name == 0 {
+		gfx.setError(errInvalidOperation)
+		return
+	}
+
+	buf := make([]byte, size)
+	if data != nil && size > 0 {
+		copy(buf, unsafe.Slice((*byte)(data), size))
+	}
+	gfx.buffers[name] = buf
+}
+
+// BufferSubData copies size bytes from data into the buffer bound to target
+// starting at the offset.
+func (gfx *GraphicsImpl) BufferSubData(target graphics.Enum, offset int, size int, data unsafe.Pointer) {
+	name, okay := gfx.boundBuffers[target]
+	if !okay || name == 0 {
+		gfx.setError(errInvalidOperation)
+		return
+	}
+	buf := gfx.buffers[name]
+	if offset < 0 || offset+size > len(buf) {
+		gfx.setError(errInvalidValue)
+		return
+	}
+	if data != nil && size > 0 {
+		copy(buf[offset:], unsafe.Slice((*byte)(data), size))
+	}
+}
+
+// CheckFramebufferStatus always reports that the framebuffer is complete.
+func (gfx *GraphicsImpl) CheckFramebufferStatus(target graphics.Enum) graphics.Enum {
+	const framebufferComplete = 0x8CD5
+	return framebufferComplete
+}
+
+// Clear fills the framebuffer with the clear color if the color buffer bit
+// is set. The scissor box is respected like it is in OpenGL.
+func (gfx *GraphicsImpl) Clear(mask graphics.Enum) {
+	if mask&graphics.COLOR_BUFFER_BIT == 0 {
+		return
+	}
+
+	c := color.RGBA{
+		R: floatToByte(gfx.clearColor[0]),
+		G: floatToByte(gfx.clearColor[1]),
+		B: floatToByte(gfx.clearColor[2]),
+		A: floatToByte(gfx.clearColor[3]),
+	}
+
+	minX, minY, maxX, maxY := gfx.rasterBounds(false)
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
+			gfx.Framebuffer.SetRGBA(x, gfx.Framebuffer.Rect.Dy()-1-y, c)
+		}
+	}
+}
+
+// ClearColor sets the color used by Clear().
+func (gfx *GraphicsImpl) ClearColor(red, green, blue, alpha float32) {
+	gfx.clearColor = [4]float32{red, green, blue, alpha}
+}
+
+// CompileShader is a no-op for the software renderer.
+func (gfx *GraphicsImpl) CompileShader(s graphics.Shader) {}
+
+// CreateProgram returns a new program name.
+func (gfx *GraphicsImpl) CreateProgram() graphics.Program {
+	return graphics.Program(gfx.genName())
+}
+
+// CreateShader returns a new shader name.
+func (gfx *GraphicsImpl) CreateShader(ty graphics.Enum) graphics.Shader {
+	return graphics.Shader(gfx.genName())
+}
+
+// CullFace is a no-op; the software renderer doesn't cull.
+func (gfx *GraphicsImpl) CullFace(mode graphics.Enum) {}
+
+// DeleteBuffer releases the memory held by the buffer.
+func (gfx *GraphicsImpl) DeleteBuffer(b graphics.Buffer) {
+	delete(gfx.buffers, b)
+}
+
+// DeleteFramebuffer is a no-op for the software renderer.
+func (gfx *GraphicsImpl) DeleteFramebuffer(fb graphics.Buffer) {}
+
+// DeleteProgram is a no-op for the software renderer.
+func (gfx *GraphicsImpl) DeleteProgram(p graphics.Program) {}
+
+// DeleteRenderbuffer is a no-op for the software renderer.
+func (gfx *GraphicsImpl) DeleteRenderbuffer(rb graphics.Buffer) {}
+
+// DeleteShader is a no-op for the software renderer.
+func (gfx *GraphicsImpl) DeleteShader(s graphics.Shader) {}
+
+// DeleteTexture releases the memory held by the texture.
+func (gfx *GraphicsImpl) DeleteTexture(v graphics.Texture) {
+	delete(gfx.textures, v)
+}
+
+// DeleteVertexArray is a no-op for the software renderer.
+func (gfx *GraphicsImpl) DeleteVertexArray(a uint32) {}
+
+// DepthMask is a no-op; there is no depth buffer.
+func (gfx *GraphicsImpl) DepthMask(flag bool) {}
+
+// Disable turns off the scissor test or blending.
+func (gfx *GraphicsImpl) Disable(e graphics.Enum) {
+	switch e {
+	case graphics.SCISSOR_TEST:
+		gfx.scissorTest = false
+	case graphics.BLEND:
+		gfx.blend = false
+	}
+}
+
+// DrawBuffers is a no-op for the software renderer.
+func (gfx *GraphicsImpl) DrawBuffers(buffers []uint32) {}
+
+// DrawElements rasterizes count/3 triangles using the indexes in the bound
+// element array buffer starting at the byte offset passed in as indices.
+func (gfx *GraphicsImpl) DrawElements(mode graphics.Enum, count int32, ty graphics.Enum, indices unsafe.Pointer) {
+	if mode != graphics.TRIANGLES || ty != graphics.UNSIGNED_INT {
+		gfx.setError(errInvalidEnum)
+		return
+	}
+
+	indexBuffer := gfx.buffers[gfx.boundBuffers[graphics.ELEMENT_ARRAY_BUFFER]]
+	offset := int(uintptr(indices))
+	if offset+int(count)*4 > len(indexBuffer) {
+		gfx.setError(errInvalidOperation)
+		return
+	}
+
+	var tri [3]vertex
+	for i := 0; i+2 < int(count); i += 3 {
+		for v := 0; v < 3; v++ {
+			idx := binary.LittleEndian.Uint32(indexBuffer[offset+(i+v)*4:])
+			tri[v] = gfx.fetchVertex(int(idx))
+		}
+		gfx.rasterizeTriangle(tri)
+	}
+}
+
+// Enable turns on the scissor test or blending.
+func (gfx *GraphicsImpl) Enable(e graphics.Enum) {
+	switch e {
+	case graphics.SCISSOR_TEST:
+		gfx.scissorTest = true
+	case graphics.BLEND:
+		gfx.blend = true
+	}
+}
+
+// EnableVertexAttribArray enables the vertex attribute at the location.
+func (gfx *GraphicsImpl) EnableVertexAttribArray(a uint32) {
+	if a >= attribCount {
+		gfx.setError(errInvalidValue)
+		return
+	}
+	gfx.attribs[a].enabled = true
+}
+
+// Flush is a no-op; all drawing is done immediately.
+func (gfx *GraphicsImpl) Flush() {}
+
+// FramebufferRenderbuffer is a no-op for the software renderer.
+func (gfx *GraphicsImpl) FramebufferRenderbuffer(target, attachment, renderbuffertarget graphics.Enum, renderbuffer graphics.Buffer) {
+}
+
+// FramebufferTexture2D is a no-op for the software renderer.
+func (gfx *GraphicsImpl) FramebufferTexture2D(target, attachment, textarget graphics.Enum, texture graphics.Texture, level int32) {
+}
+
+// GenBuffer returns a new buffer name.
+func (gfx *GraphicsImpl) GenBuffer() graphics.Buffer {
+	return graphics.Buffer(gfx.genName())
+}
+
+// GenerateMipmap is a no-op; only the base level is used.
+func (gfx *GraphicsImpl) GenerateMipmap(t graphics.Enum) {}
+
+// GenFramebuffer returns a new framebuffer name.
+func (gfx *GraphicsImpl) GenFramebuffer() graphics.Buffer {
+	return graphics.Buffer(gfx.genName())
+}
+
+// GenRenderbuffer returns a new renderbuffer name.
+func (gfx *GraphicsImpl) GenRenderbuffer() graphics.Buffer {
+	return graphics.Buffer(gfx.genName())
+}
+
+// GenTexture returns a new texture name.
+func (gfx *GraphicsImpl) GenTexture() graphics.Texture {
+	return graphics.Texture(gfx.genName())
+}
+
+// GenVertexArray returns a new vertex array name.
+func (gfx *GraphicsImpl) GenVertexArray() uint32 {
+	return gfx.genName()
+}
+
+// GetAttribLocation returns the location of the eweygewey shader attributes
+// or -1 if the name isn't known.
+func (gfx *GraphicsImpl) GetAttribLocation(p graphics.Program, name string) int32 {
+	switch name {
+	case "VERTEX_POSITION":
+		return attribPosition
+	case "VERTEX_UV":
+		return attribUV
+	case "VERTEX_TEXTURE_INDEX":
+		return attribTextureIndex
+	case "VERTEX_COLOR":
+		return attribColor
+	}
+	return -1
+}
+
+// GetError returns the first error recorded since the last call and then
+// resets the error state.
+func (gfx *GraphicsImpl) GetError() uint32 {
+	err := gfx.lastError
+	gfx.lastError = graphics.NO_ERROR
+	return err
+}
+
+// GetProgramInfoLog always returns an empty log.
+func (gfx *GraphicsImpl) GetProgramInfoLog(p graphics.Program) string {
+	return ""
+}
+
+// GetProgramiv reports success for every status query.
+func (gfx *GraphicsImpl) GetProgramiv(p graphics.Program, pname graphics.Enum, params *int32) {
+	*params = graphics.TRUE
+}
+
+// GetShaderInfoLog always returns an empty log.
+func (gfx *GraphicsImpl) GetShaderInfoLog(s graphics.Shader) string {
+	return ""
+}
+
+// GetShaderiv reports success for every status query.
+func (gfx *GraphicsImpl) GetShaderiv(s graphics.Shader, pname graphics.Enum, params *int32) {
+	*params = graphics.TRUE
+}
+
+// GetUniformLocation returns the location of the VIEW matrix and the TEX[n]
+// samplers or -1 if the name isn't known.
+func (gfx *GraphicsImpl) GetUniformLocation(p graphics.Program, name string) int32 {
+	if name == "VIEW" {
+		return uniformView
+	}
+
+	var samplerIndex int
+	if strings.HasPrefix(name, "TEX[") {
+		_, err := fmt.Sscanf(name, "TEX[%d]", &samplerIndex)
+		if err == nil && samplerIndex >= 0 && samplerIndex < maxSamplers {
+			return int32(uniformSamplerBase + samplerIndex)
+		}
+	}
+
+	return -1
+}
+
+// LinkProgram is a no-op for the software renderer.
+func (gfx *GraphicsImpl) LinkProgram(p graphics.Program) {}
+
+// PolygonMode is a no-op; polygons are always filled.
+func (gfx *GraphicsImpl) PolygonMode(face, mode graphics.Enum) {}
+
+// PolygonOffset is a no-op; there is no depth buffer.
+func (gfx *GraphicsImpl) PolygonOffset(factor float32, units float32) {}
+
+// Ptr takes a pointer or a slice and returns a pointer to the first element.
+func (gfx *GraphicsImpl) Ptr(data interface{}) unsafe.Pointer {
+	if data == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return v.UnsafePointer()
+	}
+
+	panic(fmt.Errorf("unsupported type %v passed to Ptr()", v.Type()))
+}
+
+// PtrOffset takes an offset in bytes and returns it as a pointer.
+func (gfx *GraphicsImpl) PtrOffset(offset int) unsafe.Pointer {
+	return unsafe.Add(nil, offset)
+}
+
+// ReadBuffer is a no-op for the software renderer.
+func (gfx *GraphicsImpl) ReadBuffer(src graphics.Enum) {}
+
+// RenderbufferStorage is a no-op for the software renderer.
+func (gfx *GraphicsImpl) RenderbufferStorage(target graphics.Enum, internalformat graphics.Enum, width int32, height int32) {
+}
+
+// Scissor sets the scissor box in window coordinates with the origin in the
+// lower left corner.
+func (gfx *GraphicsImpl) Scissor(x, y, w, h int32) {
+	gfx.scissorBox = [4]int32{x, y, w, h}
+}
+
+// ShaderSource is a no-op; the eweygewey shader is emulated in Go.
+func (gfx *GraphicsImpl) ShaderSource(s graphics.Shader, source string) {}
+
+// TexImage2D copies RGBA8 pixel data into the texture bound to the active unit.
+func (gfx *GraphicsImpl) TexImage2D(target graphics.Enum, level int32, intfmt int32, width int32, height int32, border int32, format graphics.Enum, ty graphics.Enum, ptr unsafe.Pointer, dataLength int) {
+	tex := gfx.boundTexture(gfx.activeUnit)
+	if tex == nil || target != graphics.TEXTURE_2D {
+		gfx.setError(errInvalidOperation)
+		return
+	}
+	if format != graphics.RGBA || ty != graphics.UNSIGNED_BYTE {
+		gfx.setError(errInvalidEnum)
+		return
+	}
+
+	// only the base level gets sampled
+	if level != 0 {
+		return
+	}
+
+	tex.width = int(width)
+	tex.height = int(height)
+	tex.pix = make([]byte, int(width)*int(height)*4)
+	if ptr != nil && dataLength > 0 {
+		copy(tex.pix, unsafe.Slice((*byte)(ptr), dataLength))
+	}
+}
+
+// TexParameterf is a no-op for the software renderer.
+func (gfx *GraphicsImpl) TexParameterf(target graphics.Enum, pname graphics.Enum, param float32) {}
+
+// TexParameterfv is a no-op for the software renderer.
+func (gfx *GraphicsImpl) TexParameterfv(target graphics.Enum, pname graphics.Enum, params *float32) {}
+
+// TexParameteri records the filtering and wrapping modes for the texture
+// bound to the active unit.
+func (gfx *GraphicsImpl) TexParameteri(target graphics.Enum, pname graphics.Enum, param int32) {
+	tex := gfx.boundTexture(gfx.activeUnit)
+	if tex == nil {
+		gfx.setError(errInvalidOperation)
+		return
+	}
+
+	switch pname {
+	case graphics.TEXTURE_MAG_FILTER:
+		tex.magFilter = param
+	case graphics.TEXTURE_WRAP_S:
+		tex.wrapS = param
+	case graphics.TEXTURE_WRAP_T:
+		tex.wrapT = param
+	}
+}
+
+// TexStorage3D is a no-op; 3D textures are not supported.
+func (gfx *GraphicsImpl) TexStorage3D(target graphics.Enum, level int32, intfmt uint32, width, height, depth int32) {
+}
+
+// TexSubImage3D is a no-op; 3D textures are not supported.
+func (gfx *GraphicsImpl) TexSubImage3D(target graphics.Enum, level, xoff, yoff, zoff, width, height, depth int32, fmt, ty graphics.Enum, ptr unsafe.Pointer) {
+}
+
+// Uniform1i sets which texture unit a TEX[n] sampler reads from.
+func (gfx *GraphicsImpl) Uniform1i(location int32, v int32) {
+	samplerIndex := location - uniformSamplerBase
+	if samplerIndex >= 0 && samplerIndex < maxSamplers {
+		gfx.samplers[samplerIndex] = v
+	}
+}
+
+// Uniform1iv sets consecutive TEX[n] samplers starting at the location.
+func (gfx *GraphicsImpl) Uniform1iv(location int32, values []int32) {
+	for i, v := range values {
+		gfx.Uniform1i(location+int32(i), v)
+	}
+}
+
+// Uniform1f is a no-op for the software renderer.
+func (gfx *GraphicsImpl) Uniform1f(location int32, v float32) {}
+
+// Uniform1fv is a no-op for the software renderer.
+func (gfx *GraphicsImpl) Uniform1fv(location int32, values []float32) {}
+
+// Uniform3f is a no-op for the software renderer.
+func (gfx *GraphicsImpl) Uniform3f(location int32, v0, v1, v2 float32) {}
+
+// Uniform3fv is a no-op for the software renderer.
+func (gfx *GraphicsImpl) Uniform3fv(location int32, values []float32) {}
+
+// Uniform4f is a no-op for the software renderer.
+func (gfx *GraphicsImpl) Uniform4f(location int32, v0, v1, v2, v3 float32) {}
+
+// Uniform4fv is a no-op for the software renderer.
+func (gfx *GraphicsImpl) Uniform4fv(location int32, values []float32) {}
+
+// UniformMatrix4fv sets the VIEW matrix.
+func (gfx *GraphicsImpl) UniformMatrix4fv(location int32, count int32, transpose bool, value mgl.Mat4) {
+	if location != uniformView {
+		return
+	}
+	if transpose {
+		value = value.Transpose()
+	}
+	gfx.view = value
+}
+
+// UniformMatrix4fvArray is a no-op for the software renderer.
+func (gfx *GraphicsImpl) UniformMatrix4fvArray(location int32, count int32, transpose bool, value []mgl.Mat4) {
+}
+
+// UseProgram is a no-op; there is only the emulated eweygewey program.
+func (gfx *GraphicsImpl) UseProgram(p graphics.Program) {}
+
+// VertexAttribPointer records where the attribute data is stored in the
+// buffer currently bound to ARRAY_BUFFER.
+func (gfx *GraphicsImpl) VertexAttribPointer(dst uint32, size int32, ty graphics.Enum, normalized bool, stride int32, ptr unsafe.Pointer) {
+	if dst >= attribCount {
+		gfx.setError(errInvalidValue)
+		return
+	}
+	if ty != graphics.FLOAT {
+		gfx.setError(errInvalidEnum)
+		return
+	}
+
+	attr := &gfx.attribs[dst]
+	attr.buffer = gfx.boundBuffers[graphics.ARRAY_BUFFER]
+	attr.size = int(size)
+	attr.stride = int(stride)
+	attr.offset = int(uintptr(ptr))
+	if attr.stride == 0 {
+		attr.stride = attr.size * 4
+	}
+}
+
+// Viewport sets the mapping from normalized device coordinates to the framebuffer.
+func (gfx *GraphicsImpl) Viewport(x, y, width, height int32) {
+	gfx.viewport = [4]int32{x, y, width, height}
+}
+
+// floatToByte converts a color component in [0..1] to [0..255].
+func floatToByte(f float32) uint8 {
+	if f <= 0.0 {
+		return 0
+	}
+	if f >= 1.0 {
+		return 255
+	}
+	return uint8(f*255.0 + 0.5)
+}
+
+// Verify that the software provider satisfies the fizzle interface.
+var _ graphics.GraphicsProvider = (*GraphicsImpl)(nil)
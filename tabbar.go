@@ -0,0 +1,305 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"fmt"
+)
+
+// TabBarOptions are the options for Window.TabBarAdv().
+type TabBarOptions struct {
+	// Closable draws a close button on each tab that removes the tab from
+	// the labels when it's clicked.
+	Closable bool
+
+	// Reorderable lets the tabs be dragged with the mouse to reorder the labels.
+	Reorderable bool
+}
+
+// TabBar draws a row of tabs with the labels and returns the index of the
+// selected tab, or -1 if there are no labels. Clicking a tab selects it and
+// while the tab bar has keyboard focus, Left and Right select the previous
+// and next tab. The selection is stored in the window with the id as the key
+// like TreeNode() stores its state. The tab bar fills the rest of the row and
+// if the tabs don't fit, they can be scrolled with the arrow buttons at the
+// end of the bar or the mouse wheel.
+func (wnd *Window) TabBar(id string, labels []string) (int, error) {
+	return wnd.TabBarAdv(id, &labels, TabBarOptions{})
+}
+
+// TabBarAdv draws a tab bar like TabBar() does with the options applied. Tabs
+// closed with their close button are removed from labels and dragging a tab
+// reorders labels, so the caller should keep the slice between frames and
+// identify the selected tab by its label. The selection follows the selected
+// tab when tabs are closed or reordered.
+func (wnd *Window) TabBarAdv(id string, labels *[]string, options TabBarOptions) (int, error) {
+	ui := wnd.Owner
+
+	// get the font for the text
+	font := ui.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return -1, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.TabMargin[0]
+	pos[1] -= wnd.Style.TabMargin[2]
+
+	// calculate the size necessary for the widget
+	_, _, wndWidth, _ := wnd.GetDisplaySize()
+	_, dimY, _ := font.GetRenderSize("0.0")
+	barW := wnd.clampWidgetWidthToReqW(wndWidth - wnd.widgetCursorDC[0] - wnd.Style.WindowPadding[1])
+	barW = barW - wnd.Style.TabMargin[0] - wnd.Style.TabMargin[1]
+	tabH := dimY + wnd.Style.TabPadding[2] + wnd.Style.TabPadding[3]
+	closeSize := tabH * 0.5
+
+	// load the selection and the scroll position for the tabs
+	selected, _ := wnd.getStoredInt(id)
+	scrollKey := id + "#scroll"
+	storedScroll, _ := wnd.getStoredInt(scrollKey)
+	scroll := float32(storedScroll)
+	keepSelectedVisible := false
+
+	// measure the tabs; offsets are from the start of the bar without scrolling
+	widths := make([]float32, len(*labels))
+	offsets := make([]float32, len(*labels))
+	var totalW float32
+	layout := func() {
+		totalW = 0
+		for i, label := range *labels {
+			dimX, _, _ := font.GetRenderSize(label)
+			widths[i] = dimX + wnd.Style.TabPadding[0] + wnd.Style.TabPadding[1]
+			if options.Closable {
+				widths[i] += closeSize + wnd.Style.TabPadding[0]
+			}
+			if i > 0 {
+				totalW += wnd.Style.TabSpacing
+			}
+			offsets[i] = totalW
+			totalW += widths[i]
+		}
+	}
+	layout()
+
+	// make room for the scroll buttons if the tabs don't fit
+	tabsW := barW
+	arrowW := tabH * 0.75
+	overflowing := totalW > barW
+	if overflowing {
+		tabsW = barW - arrowW*2
+	}
+
+	// with keyboard focus, Left and Right change the selected tab
+	focused, _ := ui.registerFocusable(id)
+	if focused && len(*labels) > 0 {
+		ui.consumeKeyEvents(func(event KeyPressEvent) bool {
+			if event.IsRune == false && event.KeyCode == EweyKeyLeft {
+				selected--
+				keepSelectedVisible = true
+				return true
+			} else if event.IsRune == false && event.KeyCode == EweyKeyRight {
+				selected++
+				keepSelectedVisible = true
+				return true
+			}
+			return false
+		})
+	}
+
+	// the close buttons get the first chance to claim a mouse press and only
+	// close their tab if the press started on them
+	closed := -1
+	closeHovered := -1
+	if options.Closable {
+		for i := range *labels {
+			closeX := pos[0] + offsets[i] - scroll + widths[i] - wnd.Style.TabPadding[1] - closeSize
+			closeY := pos[1] - (tabH-closeSize)*0.5
+			if closeX+closeSize < pos[0] || closeX > pos[0]+tabsW {
+				continue
+			}
+			buttonTest := wnd.buttonBehavior(fmt.Sprintf("%s#close%d", id, i), closeX, closeY, closeSize, closeSize)
+			mdx, mdy := wnd.getMouseDownPosition(0)
+			pressedInside := mdx > closeX && mdy > closeY-closeSize && mdx < closeX+closeSize && mdy < closeY
+			if buttonTest == buttonPressed && pressedInside {
+				closed = i
+				ui.ClearMouseButtonAction(0)
+			} else if buttonTest == buttonHover {
+				closeHovered = i
+			}
+		}
+	}
+
+	// pressing a tab selects it and starts dragging it
+	lmbStatus := ui.GetMouseButtonAction(0)
+	mx, my := wnd.getMousePosition()
+	hovered := -1
+	if my > pos[1]-tabH && my < pos[1] && mx > pos[0] && mx < pos[0]+tabsW {
+		for i := range *labels {
+			tabX := pos[0] + offsets[i] - scroll
+			if mx > tabX && mx < tabX+widths[i] {
+				hovered = i
+				break
+			}
+		}
+	}
+	if lmbStatus == MouseDown && hovered >= 0 {
+		mdx, mdy := wnd.getMouseDownPosition(0)
+		tabX := pos[0] + offsets[hovered] - scroll
+		if mdx > tabX && mdy > pos[1]-tabH && mdx < tabX+widths[hovered] && mdy < pos[1] && mdx < pos[0]+tabsW {
+			if ui.SetActiveInputID(id) {
+				ui.setFocusFromMouse(id)
+				selected = hovered
+				keepSelectedVisible = true
+			}
+		}
+	}
+
+	// dragging the selected tab past its neighbor swaps them
+	if options.Reorderable && lmbStatus == MouseDown && ui.GetActiveInputID() == id && mx >= 0 && selected >= 0 && selected < len(*labels) {
+		tabX := pos[0] + offsets[selected] - scroll
+		if selected+1 < len(*labels) && mx > tabX+widths[selected] && mx > tabX+widths[selected+1]+wnd.Style.TabSpacing {
+			(*labels)[selected], (*labels)[selected+1] = (*labels)[selected+1], (*labels)[selected]
+			selected++
+			keepSelectedVisible = true
+		} else if selected > 0 && mx < tabX && mx < tabX-wnd.Style.TabSpacing-widths[selected-1]+widths[selected] {
+			(*labels)[selected], (*labels)[selected-1] = (*labels)[selected-1], (*labels)[selected]
+			selected--
+			keepSelectedVisible = true
+		}
+		layout()
+	}
+
+	// remove a closed tab and keep the same tab selected if it's still there
+	if closed >= 0 {
+		*labels = append((*labels)[:closed], (*labels)[closed+1:]...)
+		widths = append(widths[:closed], widths[closed+1:]...)
+		offsets = offsets[:len(*labels)]
+		layout()
+		if closed < selected || selected >= len(*labels) {
+			selected--
+		}
+		hovered, closeHovered = -1, -1
+		keepSelectedVisible = true
+	}
+
+	// keep the selection within the tabs
+	if selected >= len(*labels) {
+		selected = len(*labels) - 1
+	}
+	if selected < 0 && len(*labels) > 0 {
+		selected = 0
+	}
+	wnd.setStoredInt(id, selected)
+
+	// handle the scroll buttons and the mouse wheel
+	maxScroll := totalW - tabsW
+	if overflowing {
+		leftX := pos[0] + tabsW
+		rightX := leftX + arrowW
+		if wnd.buttonBehavior(id+"#left", leftX, pos[1], arrowW, tabH) == buttonPressed {
+			// scroll to the start of the first tab cut off on the left
+			for i := len(offsets) - 1; i >= 0; i-- {
+				if offsets[i] < scroll {
+					scroll = offsets[i]
+					break
+				}
+			}
+			ui.ClearMouseButtonAction(0)
+		}
+		if wnd.buttonBehavior(id+"#right", rightX, pos[1], arrowW, tabH) == buttonPressed {
+			// scroll to the end of the first tab cut off on the right
+			for i := range offsets {
+				if offsets[i]+widths[i] > scroll+tabsW {
+					scroll = offsets[i] + widths[i] - tabsW
+					break
+				}
+			}
+			ui.ClearMouseButtonAction(0)
+		}
+		if mx > pos[0] && my > pos[1]-tabH && mx < pos[0]+barW && my < pos[1] {
+			scroll -= ui.GetScrollWheelDelta(true)
+			ui.wheelCaptured = true
+		}
+	}
+	if keepSelectedVisible && selected >= 0 {
+		if offsets[selected] < scroll {
+			scroll = offsets[selected]
+		} else if offsets[selected]+widths[selected] > scroll+tabsW {
+			scroll = offsets[selected] + widths[selected] - tabsW
+		}
+	}
+	if scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0.0 {
+		scroll = 0.0
+	}
+	wnd.setStoredInt(scrollKey, int(scroll))
+	scroll = float32(int(scroll))
+
+	// render the tabs clipped to the area left of the scroll buttons
+	tabCmd := wnd.addClippedCmd(pos[0], pos[1], tabsW, tabH)
+	for i, label := range *labels {
+		tabX := pos[0] + offsets[i] - scroll
+		bgColor := wnd.Style.TabColor
+		if i == selected {
+			bgColor = wnd.Style.TabActiveColor
+		} else if i == hovered {
+			bgColor = wnd.Style.TabHoverColor
+		}
+		combos, indexes, fc := tabCmd.DrawRectFilledDC(tabX, pos[1], tabX+widths[i], pos[1]-tabH, bgColor, defaultTextureSampler, ui.whitePixelUv)
+		tabCmd.AddFaces(combos, indexes, fc)
+
+		textPos := pos
+		textPos[0] = tabX + wnd.Style.TabPadding[0]
+		textPos[1] -= wnd.Style.TabPadding[2]
+		renderData := font.CreateText(textPos, wnd.Style.TabTextColor, label)
+		tabCmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+
+		if options.Closable {
+			closeX := tabX + widths[i] - wnd.Style.TabPadding[1] - closeSize
+			closeY := pos[1] - (tabH-closeSize)*0.5
+			if i == closeHovered {
+				combos, indexes, fc = tabCmd.DrawRectFilledDC(closeX, closeY, closeX+closeSize, closeY-closeSize, wnd.Style.ButtonHoverColor, defaultTextureSampler, ui.whitePixelUv)
+				tabCmd.AddFaces(combos, indexes, fc)
+			}
+			inset := closeSize * 0.2
+			combos, indexes, fc = tabCmd.drawCrossIcon(closeX+inset, closeY-inset, closeX+closeSize-inset, closeY-closeSize+inset, 2.0, wnd.Style.TabTextColor, defaultTextureSampler, ui.whitePixelUv)
+			tabCmd.AddFaces(combos, indexes, fc)
+		}
+	}
+	cmd := wnd.addNewCmd()
+
+	// render the scroll buttons, dimming the ones that can't scroll further
+	if overflowing {
+		arrowX := pos[0] + tabsW
+		for i, enabled := range []bool{scroll > 0.0, scroll < maxScroll} {
+			color := wnd.Style.TabTextColor
+			if !enabled {
+				color = wnd.Style.TabColor
+			}
+			x1 := arrowX + arrowW*float32(i) + arrowW*0.3
+			x2 := arrowX + arrowW*float32(i) + arrowW*0.7
+			y1 := pos[1] - tabH*0.3
+			y2 := pos[1] - tabH*0.7
+			var combos []float32
+			var indexes []uint32
+			var fc uint32
+			if i == 0 {
+				combos, indexes, fc = cmd.drawTriangleDC(x2, y1, x1, (y1+y2)*0.5, x2, y2, color, defaultTextureSampler, ui.whitePixelUv)
+			} else {
+				combos, indexes, fc = cmd.drawTriangleDC(x1, y1, x1, y2, x2, (y1+y2)*0.5, color, defaultTextureSampler, ui.whitePixelUv)
+			}
+			cmd.AddFaces(combos, indexes, fc)
+		}
+	}
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], barW, tabH)
+
+	// advance the cursor for the width of the widget
+	wnd.addCursorHorizontalDelta(barW + wnd.Style.TabMargin[0] + wnd.Style.TabMargin[1])
+	wnd.setNextRowCursorOffset(tabH + wnd.Style.TabMargin[2] + wnd.Style.TabMargin[3])
+
+	return selected, nil
+}
@@ -0,0 +1,556 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"fmt"
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TableColumnStretch columns share the width left over by the other
+	// columns in proportion to their TableColumn.Width, which counts as 1.0
+	// if it's not set.
+	TableColumnStretch = iota
+
+	// TableColumnFixed columns are TableColumn.Width wide in screen-normalized
+	// space.
+	TableColumnFixed
+
+	// TableColumnAutoFit columns are as wide as their widest cell or header
+	// was last frame.
+	TableColumnAutoFit
+)
+
+const (
+	// TableSortNone means that no column of the table has been clicked to
+	// sort by yet.
+	TableSortNone = iota
+
+	// TableSortAscending means that the rows should be sorted from the
+	// smallest to the largest value of the column.
+	TableSortAscending
+
+	// TableSortDescending means that the rows should be sorted from the
+	// largest to the smallest value of the column.
+	TableSortDescending
+)
+
+// TableColumn describes a column of a table for Window.BeginTable().
+type TableColumn struct {
+	// Label is the text shown in the header row.
+	Label string
+
+	// Sizing is how the width of the column is picked; it's one of the
+	// TableColumn* constants.
+	Sizing int
+
+	// Width is the width of fixed columns in screen-normalized space and the
+	// share of the width left over that stretched columns get.
+	Width float32
+}
+
+// TableOptions are the options for Window.BeginTableAdv().
+type TableOptions struct {
+	// Header draws a header row with the labels of the columns.
+	Header bool
+
+	// Sortable lets the headers be clicked to pick the column to sort by and
+	// to flip the sort direction; see TableSortSpec.
+	Sortable bool
+
+	// Resizable lets the borders between the columns be dragged to resize
+	// the column on the left of the border.
+	Resizable bool
+
+	// Borders draws lines around the table, between the columns and under
+	// the header row with Style.TableBorderColor and Style.TableBorderWidth.
+	Borders bool
+
+	// AlternateRows draws every other row with Style.TableRowAltColor
+	// instead of Style.TableRowColor.
+	AlternateRows bool
+}
+
+// TableSortSpec is the sorting picked with the headers of a sortable table.
+// The table doesn't sort anything itself; the caller sorts the rows before
+// building them.
+type TableSortSpec struct {
+	// Column is the index of the column to sort by or -1 if none was picked.
+	Column int
+
+	// Direction is one of the TableSort* constants.
+	Direction int
+
+	// Changed is true on the frame a header was clicked.
+	Changed bool
+}
+
+// tableState is the state of a table between BeginTable() and EndTable().
+type tableState struct {
+	// id is the id of the table, which is also the start of the keys its
+	// column widths and sorting are stored under.
+	id string
+
+	// columns and options are what the table was started with.
+	columns []TableColumn
+	options TableOptions
+
+	// isColumns is true if the table was started with Window.Columns().
+	isColumns bool
+
+	// pos is the top-left corner of the table in display coordinates.
+	pos mgl.Vec3
+
+	// availWidthDC is the width the table had to fill when it was started.
+	availWidthDC float32
+
+	// widthDC is the total width of the columns.
+	widthDC float32
+
+	// offsets are the distances from the left side of the table to the
+	// columns and widths are their widths, all in display coordinates.
+	offsets []float32
+	widths  []float32
+
+	// fitWidths are the widest the cells and headers of the columns have
+	// been this frame, which auto-fit columns are sized to next frame.
+	fitWidths []float32
+
+	// headerH is the height of the header row in display coordinates.
+	headerH float32
+
+	// rowTop is the y coordinate of the top of the current row and
+	// rowHeight the height of its tallest cell so far.
+	rowTop    float32
+	rowHeight float32
+
+	// rowIndex is the index of the current row, not counting the header.
+	rowIndex   int
+	rowStarted bool
+
+	// column is the index of the current cell in the row and cellOpen is
+	// true while its widgets are being built.
+	column   int
+	cellOpen bool
+
+	// childDepth is how many regions were open when the table was started.
+	childDepth int
+
+	// bgCmd is the cmdList the backgrounds of the rows are drawn in, which
+	// comes before the cmdLists of the cells.
+	bgCmd *cmdList
+}
+
+// key returns the key a value for a column of the table is stored under.
+func (t *tableState) key(name string, column int) string {
+	return fmt.Sprintf("%s#%s%d", t.id, name, column)
+}
+
+// borderAt returns the index of the column whose right border is at the
+// display coordinate or -1 if there isn't one. The borders reach down to the
+// bottom of the table as it was last frame.
+func (t *tableState) borderAt(x, y, tableH, borderW float32) int {
+	if y > t.pos[1] || y < t.pos[1]-tableH {
+		return -1
+	}
+	for i := 0; i < len(t.columns)-1; i++ {
+		bx := t.pos[0] + t.offsets[i] + t.widths[i]
+		if x > bx-borderW*0.5 && x < bx+borderW*0.5 {
+			return i
+		}
+	}
+	return -1
+}
+
+// currentTable returns the innermost table being built or nil if no table
+// was started.
+func (wnd *Window) currentTable() *tableState {
+	if len(wnd.tableStack) == 0 {
+		return nil
+	}
+	return wnd.tableStack[len(wnd.tableStack)-1]
+}
+
+// BeginTable starts a table with the columns that fills the rest of the row.
+// The table has a header row with the labels of the columns, borders that can
+// be dragged to resize the columns and rows that alternate their background.
+// Start each row with TableNextRow() and each cell with TableNextColumn(),
+// then build the widgets of the cell, which are clipped to it. EndTable()
+// ends the table. The column widths set by dragging the borders are stored
+// in the window with the id as the start of the key like TreeNode() stores
+// its state. Returns the sorting picked by clicking the headers if the table
+// is sortable.
+func (wnd *Window) BeginTable(id string, columns []TableColumn) (TableSortSpec, error) {
+	return wnd.BeginTableAdv(id, columns, TableOptions{Header: true, Resizable: true, Borders: true, AlternateRows: true})
+}
+
+// BeginTableAdv starts a table like BeginTable() does with the options applied.
+func (wnd *Window) BeginTableAdv(id string, columns []TableColumn, options TableOptions) (TableSortSpec, error) {
+	ui := wnd.Owner
+	sort := TableSortSpec{Column: -1}
+
+	// get the font for the text
+	font := ui.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return sort, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	t := new(tableState)
+	t.id = id
+	t.columns = columns
+	t.options = options
+	t.pos = wnd.getCursorDC()
+	t.availWidthDC = wnd.takeRowWidth()
+	t.fitWidths = make([]float32, len(columns))
+	t.rowTop = t.pos[1]
+	t.rowIndex = -1
+	t.column = -1
+	t.childDepth = len(wnd.childStack)
+
+	// the header row is as tall as a line of text
+	_, dimY, _ := font.GetRenderSize("0.0")
+	arrowSize := dimY * 0.5
+	if options.Header {
+		t.headerH = dimY + wnd.Style.WindowPadding[2] + wnd.Style.WindowPadding[3]
+		for i, c := range columns {
+			dimX, _, _ := font.GetRenderSize(c.Label)
+			t.fitWidths[i] = dimX + wnd.Style.WindowPadding[0] + wnd.Style.WindowPadding[1]
+			if options.Sortable {
+				t.fitWidths[i] += arrowSize + wnd.Style.WindowPadding[0]
+			}
+		}
+	}
+
+	wnd.layoutTable(t)
+	if options.Resizable {
+		wnd.tableResizeBehavior(t)
+	}
+
+	// load the sorting; the column is stored off by one so that zero is none
+	storedColumn, _ := wnd.getStoredInt(id + "#sort")
+	sort.Column = storedColumn - 1
+	sort.Direction, _ = wnd.getStoredInt(id + "#sortdir")
+
+	// the row backgrounds go under everything in the cells
+	t.bgCmd = wnd.getLastCmd()
+
+	if options.Header {
+		lastH, _ := wnd.getStoredInt(id + "#h")
+		mdx, mdy := wnd.getMouseDownPosition(0)
+		for i, c := range columns {
+			x, y, w, h := t.pos[0]+t.offsets[i], t.rowTop, t.widths[i], t.headerH
+
+			// clicking a header sorts by its column or flips the direction
+			bgColor := wnd.Style.TableHeaderColor
+			if options.Sortable {
+				buttonTest := wnd.buttonBehavior(t.key("header", i), x, y, w, h)
+				pressedIn := mdx > x && mdy > y-h && mdx < x+w && mdy < y && t.borderAt(mdx, mdy, float32(lastH), wnd.Style.ResizeBorderWidth) < 0
+				if buttonTest == buttonPressed && pressedIn {
+					if sort.Column == i && sort.Direction == TableSortAscending {
+						sort.Direction = TableSortDescending
+					} else {
+						sort.Direction = TableSortAscending
+					}
+					sort.Column = i
+					sort.Changed = true
+					wnd.setStoredInt(id+"#sort", i+1)
+					wnd.setStoredInt(id+"#sortdir", sort.Direction)
+					ui.ClearMouseButtonAction(0)
+				} else if buttonTest == buttonHover {
+					bgColor = wnd.Style.TableHoverColor
+				}
+			}
+			combos, indexes, fc := t.bgCmd.DrawRectFilledDC(x, y, x+w, y-h, bgColor, defaultTextureSampler, ui.whitePixelUv)
+			t.bgCmd.AddFaces(combos, indexes, fc)
+
+			// the label and the sort arrow are clipped to the header
+			cmd := wnd.addClippedCmd(x, y, w, h)
+			textPos := mgl.Vec3{x + wnd.Style.WindowPadding[0], y - wnd.Style.WindowPadding[2], 0}
+			renderData := font.CreateText(textPos, wnd.Style.TextColor, c.Label)
+			cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+			if options.Sortable && sort.Column == i {
+				ax := x + w - wnd.Style.WindowPadding[1] - arrowSize
+				ay := y - h*0.5
+				if sort.Direction == TableSortAscending {
+					combos, indexes, fc = cmd.drawTriangleDC(ax, ay-arrowSize*0.5, ax+arrowSize, ay-arrowSize*0.5, ax+arrowSize*0.5, ay+arrowSize*0.5, wnd.Style.TextColor, defaultTextureSampler, ui.whitePixelUv)
+				} else {
+					combos, indexes, fc = cmd.drawTriangleDC(ax, ay+arrowSize*0.5, ax+arrowSize*0.5, ay-arrowSize*0.5, ax+arrowSize, ay+arrowSize*0.5, wnd.Style.TextColor, defaultTextureSampler, ui.whitePixelUv)
+				}
+				cmd.AddFaces(combos, indexes, fc)
+			}
+			wnd.addNewCmd()
+		}
+		t.rowTop -= t.headerH
+	}
+
+	wnd.tableStack = append(wnd.tableStack, t)
+	return sort, nil
+}
+
+// layoutTable calculates the offsets and widths of the columns of the table.
+// Columns resized by dragging their border keep the width they were dragged
+// to and the stretched columns share what the other columns leave over.
+func (wnd *Window) layoutTable(t *tableState) {
+	minWidth := wnd.Style.WindowPadding[0] + wnd.Style.WindowPadding[1]
+	t.offsets = make([]float32, len(t.columns))
+	t.widths = make([]float32, len(t.columns))
+	stretched := make([]bool, len(t.columns))
+
+	var fixedWidth, totalWeight float32
+	for i, c := range t.columns {
+		var widthDC float32
+		if storedWidth, okay := wnd.getStoredInt(t.key("w", i)); okay {
+			widthDC = float32(storedWidth)
+		} else if c.Sizing == TableColumnFixed {
+			widthDC, _ = wnd.Owner.ScreenToDisplay(c.Width, 0.0)
+		} else if c.Sizing == TableColumnAutoFit {
+			fitWidth, _ := wnd.getStoredInt(t.key("fit", i))
+			widthDC = float32(fitWidth)
+			if t.fitWidths[i] > widthDC {
+				widthDC = t.fitWidths[i]
+			}
+		} else {
+			stretched[i] = true
+			totalWeight += tableColumnWeight(c)
+			continue
+		}
+		if widthDC < minWidth {
+			widthDC = minWidth
+		}
+		t.widths[i] = widthDC
+		fixedWidth += widthDC
+	}
+
+	var offset float32
+	for i, c := range t.columns {
+		if stretched[i] {
+			t.widths[i] = (t.availWidthDC - fixedWidth) * tableColumnWeight(c) / totalWeight
+			if t.widths[i] < minWidth {
+				t.widths[i] = minWidth
+			}
+		}
+		t.offsets[i] = offset
+		offset += t.widths[i]
+	}
+	t.widthDC = offset
+}
+
+// tableColumnWeight returns the share of the width left over that a
+// stretched column gets.
+func tableColumnWeight(c TableColumn) float32 {
+	if c.Width <= 0.0 {
+		return 1.0
+	}
+	return c.Width
+}
+
+// tableResizeBehavior lets the borders between the columns be dragged to set
+// the width of the column on the left of the border.
+func (wnd *Window) tableResizeBehavior(t *tableState) {
+	ui := wnd.Owner
+	lastH, present := wnd.getStoredInt(t.id + "#h")
+	tableH := float32(lastH)
+	if !present {
+		tableH = t.headerH
+	}
+	borderW := wnd.Style.ResizeBorderWidth
+
+	if ui.lmbPressed {
+		mdx, mdy := wnd.getMouseDownPosition(0)
+		if i := t.borderAt(mdx, mdy, tableH, borderW); i >= 0 {
+			ui.SetActiveInputID(t.key("resize", i))
+		}
+	}
+
+	mx, my := wnd.getMousePosition()
+	lmbDown := ui.GetMouseButtonAction(0) == MouseDown
+	for i := 0; i < len(t.columns)-1; i++ {
+		dragging := lmbDown && ui.GetActiveInputID() == t.key("resize", i)
+		if dragging {
+			widthDC := mx - t.pos[0] - t.offsets[i]
+			if minWidth := wnd.Style.WindowPadding[0] + wnd.Style.WindowPadding[1]; widthDC < minWidth {
+				widthDC = minWidth
+			}
+			wnd.setStoredInt(t.key("w", i), int(widthDC))
+			wnd.layoutTable(t)
+		}
+		if dragging || (ui.GetActiveInputID() == "" && t.borderAt(mx, my, tableH, borderW) == i) {
+			ui.cursorShape = CursorResizeEW
+		}
+	}
+}
+
+// TableNextRow starts a new row in the table started last, ending the
+// current row.
+func (wnd *Window) TableNextRow() {
+	t := wnd.currentTable()
+	if t == nil {
+		return
+	}
+	wnd.endTableRow(t)
+	t.rowIndex++
+	t.rowStarted = true
+	t.rowHeight = 0.0
+	t.column = -1
+}
+
+// TableNextColumn starts the next cell in the current row of the table
+// started last, ending the current cell. After the last cell of a row or if
+// no row was started, it starts a new row. Returns false if the cell is
+// outside of the visible area, in which case its widgets don't need to be
+// built.
+func (wnd *Window) TableNextColumn() bool {
+	t := wnd.currentTable()
+	if t == nil || len(t.columns) == 0 {
+		return false
+	}
+	if !t.rowStarted || t.column >= len(t.columns)-1 {
+		wnd.TableNextRow()
+	} else {
+		wnd.endTableCell(t)
+	}
+	t.column++
+
+	// the cell reaches down to the bottom of the visible area until the
+	// height of the row is known
+	x := t.pos[0] + t.offsets[t.column]
+	heightDC := t.rowTop - wnd.contentBottomDC() + wnd.Style.WindowPadding[3]
+	if heightDC < 0.0 {
+		heightDC = 0.0
+	}
+	cell := new(childState)
+	cell.isCell = true
+	cell.rect = mgl.Vec4{x, t.rowTop, t.widths[t.column], heightDC}
+	cell.contentRect = cell.rect
+	wnd.beginRegion(cell)
+	t.cellOpen = true
+
+	clip := wnd.currentClipRect()
+	return clip[2] > 0.0 && clip[3] > 0.0
+}
+
+// endTableCell ends the open cell of the table and anything left open in it.
+func (wnd *Window) endTableCell(t *tableState) {
+	if !t.cellOpen {
+		return
+	}
+	wnd.endLayoutsTo(t.childDepth + 1)
+	_, heightDC, widthDC := wnd.endRegion()
+	t.cellOpen = false
+
+	if heightDC > t.rowHeight {
+		t.rowHeight = heightDC
+	}
+	if widthDC > t.fitWidths[t.column] {
+		t.fitWidths[t.column] = widthDC
+	}
+}
+
+// endTableRow ends the current row of the table and draws its background
+// now that its height is known.
+func (wnd *Window) endTableRow(t *tableState) {
+	wnd.endTableCell(t)
+	if !t.rowStarted {
+		return
+	}
+	t.rowStarted = false
+
+	bgColor := wnd.Style.TableRowColor
+	if t.options.AlternateRows && t.rowIndex%2 == 1 {
+		bgColor = wnd.Style.TableRowAltColor
+	}
+	combos, indexes, fc := t.bgCmd.DrawRectFilledDC(t.pos[0], t.rowTop, t.pos[0]+t.widthDC, t.rowTop-t.rowHeight, bgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	t.bgCmd.AddFaces(combos, indexes, fc)
+	t.rowTop -= t.rowHeight
+}
+
+// EndTable ends the table started last and places it in the window like any
+// other widget.
+func (wnd *Window) EndTable() {
+	t := wnd.currentTable()
+	if t == nil {
+		return
+	}
+	wnd.endTableRow(t)
+	wnd.tableStack = wnd.tableStack[:len(wnd.tableStack)-1]
+	tableH := t.pos[1] - t.rowTop
+
+	// draw the borders on top of the cells
+	if t.options.Borders {
+		cmd := wnd.getLastCmd()
+		border := wnd.Style.TableBorderWidth
+		left, top, right, bottom := t.pos[0], t.pos[1], t.pos[0]+t.widthDC, t.rowTop
+		combos, indexes, fc := cmd.DrawRectOutlineDC(left, top, right, bottom, border, wnd.Style.TableBorderColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+		cmd.AddFaces(combos, indexes, fc)
+		for i := 0; i < len(t.columns)-1; i++ {
+			x := left + t.offsets[i] + t.widths[i]
+			combos, indexes, fc = cmd.DrawRectFilledDC(x-border*0.5, top, x+border*0.5, bottom, wnd.Style.TableBorderColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			cmd.AddFaces(combos, indexes, fc)
+		}
+		if t.options.Header {
+			y := top - t.headerH
+			combos, indexes, fc = cmd.DrawRectFilledDC(left, y+border*0.5, right, y-border*0.5, wnd.Style.TableBorderColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			cmd.AddFaces(combos, indexes, fc)
+		}
+	}
+
+	// remember the height for the resizable borders and the widths for the
+	// auto-fit columns for the next frame
+	wnd.setStoredInt(t.id+"#h", int(tableH))
+	for i, fitWidth := range t.fitWidths {
+		wnd.setStoredInt(t.key("fit", i), int(math.Ceil(float64(fitWidth))))
+	}
+
+	// advance the cursor past the table
+	wnd.addCursorHorizontalDelta(t.widthDC)
+	wnd.setNextRowCursorOffset(tableH)
+}
+
+// Columns lays out the following widgets in count columns of the same width
+// that fill the rest of the row and can be resized by dragging the borders
+// between them. The widgets start in the first column and NextColumn() moves
+// to the next one, wrapping around to a new row below the tallest column
+// after the last one. Calling Columns(1) goes back to a single column. Column
+// sets with the same count in a window share their widths.
+func (wnd *Window) Columns(count int) error {
+	if t := wnd.currentTable(); t != nil && t.isColumns {
+		wnd.EndTable()
+	}
+	if count < 2 {
+		return nil
+	}
+
+	_, err := wnd.BeginTableAdv(fmt.Sprintf("#columns%d", count), make([]TableColumn, count), TableOptions{Resizable: true, Borders: true})
+	if err != nil {
+		return err
+	}
+	wnd.currentTable().isColumns = true
+	wnd.TableNextColumn()
+	return nil
+}
+
+// NextColumn moves to the next column of the columns started with Columns().
+func (wnd *Window) NextColumn() {
+	wnd.TableNextColumn()
+}
+
+// endLayoutsTo ends the child regions and tables that were started while at
+// least depth regions were open, innermost first.
+func (wnd *Window) endLayoutsTo(depth int) {
+	for {
+		t := wnd.currentTable()
+		top := wnd.currentChild()
+		open := len(wnd.childStack)
+		if t != nil && t.childDepth >= depth && (open == t.childDepth || (open == t.childDepth+1 && top.isCell)) {
+			wnd.EndTable()
+		} else if open > depth && !top.isCell {
+			wnd.EndChild()
+		} else {
+			return
+		}
+	}
+}
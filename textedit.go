@@ -0,0 +1,444 @@
+// Copyright 2016, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package eweygewey
+
+import (
+	"unicode"
+)
+
+// these are the filters that can be used to restrict the runes an Editbox accepts
+const (
+	EditboxFilterNone  = 0 // accept all runes
+	EditboxFilterInt   = 1 // accept ASCII digits and signs
+	EditboxFilterFloat = 2 // accept ASCII digits, signs, the decimal point and exponents
+	EditboxFilterHex   = 3 // accept hexadecimal digits
+)
+
+// these are the flags returned by Window.EditboxAdv()
+const (
+	EditboxChanged   = 1 // the value was changed this frame
+	EditboxCommitted = 2 // the user pressed Enter to finish editing this frame
+)
+
+// EditboxOptions control the behavior of an editbox created with Window.EditboxAdv().
+type EditboxOptions struct {
+	// Filter is one of the EditboxFilter* constants restricting the runes
+	// that can be typed or pasted.
+	Filter int
+
+	// FilterFunc is an optional function that returns false for runes that
+	// should not be accepted. It is used in addition to Filter.
+	FilterFunc func(rune) bool
+
+	// MaxLength is the maximum number of runes in the value; 0 is unlimited.
+	MaxLength int
+
+	// Password masks the value when drawing and disables copying it; cutting
+	// the selection only deletes it.
+	Password bool
+
+	// Placeholder is the hint text drawn when the value is empty.
+	Placeholder string
+}
+
+// acceptRune returns true if the rune passes the filters of the options.
+func (opts *EditboxOptions) acceptRune(r rune) bool {
+	switch opts.Filter {
+	case EditboxFilterInt:
+		if (r < '0' || r > '9') && r != '-' && r != '+' {
+			return false
+		}
+	case EditboxFilterFloat:
+		if (r < '0' || r > '9') && r != '-' && r != '+' && r != '.' && r != 'e' && r != 'E' {
+			return false
+		}
+	case EditboxFilterHex:
+		if !unicode.Is(unicode.ASCII_Hex_Digit, r) {
+			return false
+		}
+	}
+
+	if opts.FilterFunc != nil && !opts.FilterFunc(r) {
+		return false
+	}
+	return true
+}
+
+// hasSelection returns true if a range of runes is selected.
+func (ate *textEditState) hasSelection() bool {
+	return ate.SelectionAnchor != ate.CursorOffset
+}
+
+// selectionRange returns the selected range of runes as [start .. end).
+func (ate *textEditState) selectionRange() (int, int) {
+	if ate.SelectionAnchor < ate.CursorOffset {
+		return ate.SelectionAnchor, ate.CursorOffset
+	}
+	return ate.CursorOffset, ate.SelectionAnchor
+}
+
+// moveCursor places the cursor at the rune offset. If extendSelection is
+// false the selection is collapsed to the new cursor position.
+func (ate *textEditState) moveCursor(offset int, extendSelection bool) {
+	ate.CursorOffset = offset
+	if !extendSelection {
+		ate.SelectionAnchor = offset
+	}
+}
+
+// selectRange selects the runes in [start .. end) and places the cursor at the end.
+func (ate *textEditState) selectRange(start, end int) {
+	ate.SelectionAnchor = start
+	ate.CursorOffset = end
+}
+
+// clampToLength makes sure the cursor and selection anchor fit in a string
+// with the given number of runes in case it was changed outside the editor.
+func (ate *textEditState) clampToLength(length int) {
+	if ate.CursorOffset > length {
+		ate.CursorOffset = length
+	}
+	if ate.SelectionAnchor > length {
+		ate.SelectionAnchor = length
+	}
+}
+
+// selectedText returns the selected runes as a string. Password editors
+// return an empty string so that the value can't be copied.
+func (ate *textEditState) selectedText(runes []rune) string {
+	if ate.Options.Password {
+		return ""
+	}
+
+	start, end := ate.selectionRange()
+	return string(runes[start:end])
+}
+
+// deleteSelection removes the selected runes and returns the new slice of
+// runes. The cursor is placed where the selection started.
+func (ate *textEditState) deleteSelection(runes []rune) []rune {
+	if !ate.hasSelection() {
+		return runes
+	}
+
+	start, end := ate.selectionRange()
+	runes = append(runes[:start], runes[end:]...)
+	ate.moveCursor(start, false)
+	return runes
+}
+
+// insertText replaces the selection, if any, with the runes to insert and
+// returns the new slice of runes. The cursor is placed after the insertion.
+// Runes rejected by the editor options are dropped and the insertion is
+// shortened to respect the maximum length.
+func (ate *textEditState) insertText(runes []rune, insert []rune) []rune {
+	accepted := make([]rune, 0, len(insert))
+	for _, r := range insert {
+		if ate.Options.acceptRune(r) {
+			accepted = append(accepted, r)
+		}
+	}
+	insert = accepted
+	if len(insert) == 0 {
+		return runes
+	}
+
+	runes = ate.deleteSelection(runes)
+	if ate.Options.MaxLength > 0 {
+		room := ate.Options.MaxLength - len(runes)
+		if room <= 0 {
+			return runes
+		}
+		if len(insert) > room {
+			insert = insert[:room]
+		}
+	}
+
+	runes = insertRunes(runes, ate.CursorOffset, insert)
+	ate.moveCursor(ate.CursorOffset+len(insert), false)
+	return runes
+}
+
+// snapshot returns the current text and cursor state for the undo history.
+func (ate *textEditState) snapshot(runes []rune) textEditSnapshot {
+	return textEditSnapshot{
+		Text:            string(runes),
+		CursorOffset:    ate.CursorOffset,
+		SelectionAnchor: ate.SelectionAnchor,
+	}
+}
+
+// restore sets the cursor state from the snapshot and returns its text.
+func (ate *textEditState) restore(snap textEditSnapshot) []rune {
+	ate.CursorOffset = snap.CursorOffset
+	ate.SelectionAnchor = snap.SelectionAnchor
+	ate.CoalesceTyping = false
+	return []rune(snap.Text)
+}
+
+// pushUndo adds the snapshot to the undo history, dropping the oldest edits
+// if the history is deeper than maxDepth, and clears the redo history.
+func (ate *textEditState) pushUndo(snap textEditSnapshot, maxDepth int) {
+	ate.UndoStack = append(ate.UndoStack, snap)
+	if maxDepth > 0 && len(ate.UndoStack) > maxDepth {
+		ate.UndoStack = ate.UndoStack[len(ate.UndoStack)-maxDepth:]
+	}
+	ate.RedoStack = ate.RedoStack[:0]
+}
+
+// undo reverts the last edit and returns the runes from before it.
+func (ate *textEditState) undo(runes []rune) []rune {
+	if len(ate.UndoStack) == 0 {
+		return runes
+	}
+	last := ate.UndoStack[len(ate.UndoStack)-1]
+	ate.UndoStack = ate.UndoStack[:len(ate.UndoStack)-1]
+	ate.RedoStack = append(ate.RedoStack, ate.snapshot(runes))
+	return ate.restore(last)
+}
+
+// redo applies the last undone edit again and returns the runes after it.
+func (ate *textEditState) redo(runes []rune) []rune {
+	if len(ate.RedoStack) == 0 {
+		return runes
+	}
+	last := ate.RedoStack[len(ate.RedoStack)-1]
+	ate.RedoStack = ate.RedoStack[:len(ate.RedoStack)-1]
+	ate.UndoStack = append(ate.UndoStack, ate.snapshot(runes))
+	return ate.restore(last)
+}
+
+// applyEditKey applies the key event to the runes being edited for the keys
+// that behave the same in every text editor: cursor movement left and right,
+// erasing, clipboard commands, undo and redo and typing. Edits are recorded
+// in the undo history with consecutive typed runes grouped together.
+// It returns the updated runes and false if the key event was not handled.
+func (ate *textEditState) applyEditKey(ui *Manager, runes []rune, event KeyPressEvent) ([]rune, bool) {
+	// undo and redo don't get recorded in the history themselves
+	if event.IsRune && event.CtrlDown {
+		switch {
+		case event.Rune == 'Z' && event.ShiftDown, event.Rune == 'Y':
+			return ate.redo(runes), true
+		case event.Rune == 'Z':
+			return ate.undo(runes), true
+		}
+	}
+
+	before := ate.snapshot(runes)
+	typing := event.IsRune && !event.CtrlDown && !ate.hasSelection()
+	runes, handled := ate.editKey(ui, runes, event)
+	if !handled {
+		return runes, false
+	}
+
+	if string(runes) != before.Text {
+		// typed runes coalesce into the edit that started the typing
+		if !(typing && ate.CoalesceTyping) {
+			ate.pushUndo(before, ui.UndoDepth)
+		}
+		ate.CoalesceTyping = typing
+	} else {
+		// moving the cursor starts a new group of typing
+		ate.CoalesceTyping = false
+	}
+
+	return runes, true
+}
+
+// editKey does the work for applyEditKey without recording history.
+func (ate *textEditState) editKey(ui *Manager, runes []rune, event KeyPressEvent) ([]rune, bool) {
+	if event.IsRune == false {
+		switch event.KeyCode {
+		case EweyKeyRight:
+			if event.CtrlDown {
+				ate.moveCursor(wordEndAfter(runes, ate.CursorOffset), event.ShiftDown)
+			} else if ate.hasSelection() && !event.ShiftDown {
+				_, end := ate.selectionRange()
+				ate.moveCursor(end, false)
+			} else if ate.CursorOffset < len(runes) {
+				ate.moveCursor(ate.CursorOffset+1, event.ShiftDown)
+			}
+		case EweyKeyLeft:
+			if event.CtrlDown {
+				ate.moveCursor(wordStartBefore(runes, ate.CursorOffset), event.ShiftDown)
+			} else if ate.hasSelection() && !event.ShiftDown {
+				start, _ := ate.selectionRange()
+				ate.moveCursor(start, false)
+			} else if ate.CursorOffset > 0 {
+				ate.moveCursor(ate.CursorOffset-1, event.ShiftDown)
+			}
+		case EweyKeyBackspace:
+			if ate.hasSelection() {
+				runes = ate.deleteSelection(runes)
+			} else if ate.CursorOffset > 0 {
+				// erase the word or the rune previous to the cursor
+				start := ate.CursorOffset - 1
+				if event.CtrlDown {
+					start = wordStartBefore(runes, ate.CursorOffset)
+				}
+				ate.selectRange(start, ate.CursorOffset)
+				runes = ate.deleteSelection(runes)
+			}
+		case EweyKeyDelete:
+			if ate.hasSelection() {
+				runes = ate.deleteSelection(runes)
+			} else if ate.CursorOffset < len(runes) {
+				// erase the word or the rune just after the cursor
+				end := ate.CursorOffset + 1
+				if event.CtrlDown {
+					end = wordEndAfter(runes, ate.CursorOffset)
+				}
+				ate.selectRange(ate.CursorOffset, end)
+				runes = ate.deleteSelection(runes)
+			}
+		case EweyKeyInsert:
+			if event.ShiftDown {
+				clippy, _ := ui.GetClipboardString()
+				runes = ate.insertText(runes, []rune(clippy))
+			} else if event.CtrlDown && ate.hasSelection() && !ate.Options.Password {
+				ui.SetClipboardString(ate.selectedText(runes))
+			}
+		default:
+			return runes, false
+		}
+		return runes, true
+	}
+
+	// do some special testing for clipboard and selection commands
+	if event.CtrlDown {
+		switch event.Rune {
+		case 'A':
+			ate.selectRange(0, len(runes))
+		case 'C':
+			if ate.hasSelection() && !ate.Options.Password {
+				ui.SetClipboardString(ate.selectedText(runes))
+			}
+		case 'X':
+			// cutting a password only deletes it
+			if ate.hasSelection() {
+				if !ate.Options.Password {
+					ui.SetClipboardString(ate.selectedText(runes))
+				}
+				runes = ate.deleteSelection(runes)
+			}
+		case 'V':
+			clippy, _ := ui.GetClipboardString()
+			runes = ate.insertText(runes, []rune(clippy))
+		default:
+			return runes, false
+		}
+		return runes, true
+	}
+
+	// insert the rune into the value string, replacing the selection
+	runes = ate.insertText(runes, []rune{event.Rune})
+	return runes, true
+}
+
+// releaseTextEditor gives up the text editing focus voluntarily. If direction
+// is not 0 the keyboard focus is moved forwards or backwards as well.
+func (ui *Manager) releaseTextEditor(direction int) {
+	ui.clearActiveTextEditor()
+	ui.ClearActiveInputID()
+	if direction != 0 {
+		ui.moveFocus(direction)
+	}
+}
+
+// lineStarts returns the rune offsets for the start of every line in runes.
+func lineStarts(runes []rune) []int {
+	starts := []int{0}
+	for i, r := range runes {
+		if r == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// lineForOffset returns the index of the line that contains the rune offset.
+func lineForOffset(starts []int, offset int) int {
+	line := 0
+	for i, start := range starts {
+		if start > offset {
+			break
+		}
+		line = i
+	}
+	return line
+}
+
+// lineEnd returns the rune offset of the end of the line, not including the
+// newline character.
+func lineEnd(runes []rune, starts []int, line int) int {
+	if line+1 < len(starts) {
+		return starts[line+1] - 1
+	}
+	return len(runes)
+}
+
+// isWordRune returns true if the rune is considered part of a word for
+// word navigation and selection.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// wordStartBefore returns the offset of the start of the word before offset,
+// skipping any non-word runes in between.
+func wordStartBefore(runes []rune, offset int) int {
+	i := offset
+	for i > 0 && !isWordRune(runes[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(runes[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEndAfter returns the offset of the end of the word after offset,
+// skipping any non-word runes in between.
+func wordEndAfter(runes []rune, offset int) int {
+	i := offset
+	for i < len(runes) && !isWordRune(runes[i]) {
+		i++
+	}
+	for i < len(runes) && isWordRune(runes[i]) {
+		i++
+	}
+	return i
+}
+
+// wordRangeAt returns the [start .. end) range of the word containing the
+// rune at offset. If that rune isn't part of a word, only it is returned.
+func wordRangeAt(runes []rune, offset int) (int, int) {
+	if len(runes) == 0 {
+		return 0, 0
+	}
+	if offset >= len(runes) {
+		offset = len(runes) - 1
+	}
+	if !isWordRune(runes[offset]) {
+		return offset, offset + 1
+	}
+
+	start := offset
+	for start > 0 && isWordRune(runes[start-1]) {
+		start--
+	}
+	end := offset
+	for end < len(runes) && isWordRune(runes[end]) {
+		end++
+	}
+	return start, end
+}
+
+// insertRunes returns the runes with the insert runes placed at the index.
+func insertRunes(runes []rune, index int, insert []rune) []rune {
+	result := make([]rune, 0, len(runes)+len(insert))
+	result = append(result, runes[:index]...)
+	result = append(result, insert...)
+	result = append(result, runes[index:]...)
+	return result
+}
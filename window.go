@@ -5,6 +5,10 @@ package eweygewey
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	mgl "github.com/go-gl/mathgl/mgl32"
 )
@@ -35,12 +39,60 @@ type Window struct {
 	// of the window
 	ShowScrollBar bool
 
+	// ShowHorizontalScrollBar indicates if a scroll bar for scrolling the
+	// widgets sideways should be attached to the bottom of the window
+	ShowHorizontalScrollBar bool
+
 	// ShowTitleBar indicates if the title bar should be drawn or not
 	ShowTitleBar bool
 
+	// IsCollapsible indicates if an arrow is drawn in the title bar that folds
+	// the window down to its title bar, which double-clicking the title bar
+	// does as well.
+	IsCollapsible bool
+
+	// IsCollapsed indicates if only the title bar of the window is shown.
+	IsCollapsed bool
+
+	// ShowCloseButton indicates if a close button is drawn on the right side
+	// of the title bar.
+	ShowCloseButton bool
+
+	// IsOpen indicates if the window should be built and drawn at all. The
+	// close button sets it to false.
+	IsOpen bool
+
+	// OnClose is called after the close button sets IsOpen to false; it can
+	// set IsOpen back to true to keep the window open.
+	OnClose func(wnd *Window)
+
 	// IsMoveable indicates if the window should be moveable by LMB drags
 	IsMoveable bool
 
+	// IsResizable indicates if the window can be resized by dragging its left,
+	// right and bottom edges or the grip in the bottom-right corner.
+	IsResizable bool
+
+	// MinSize is the smallest the width and height of the window can be in
+	// screen-normalized space when it's resized.
+	MinSize mgl.Vec2
+
+	// MaxSize is the largest the width and height of the window can be in
+	// screen-normalized space when it's resized; a zero value has no limit.
+	MaxSize mgl.Vec2
+
+	// AlwaysOnTop keeps the window above all of the windows that don't have
+	// it set, no matter which one was clicked last.
+	AlwaysOnTop bool
+
+	// AlwaysOnBottom keeps the window below all of the windows that don't
+	// have it set, no matter which one was clicked last.
+	AlwaysOnBottom bool
+
+	// IsDockable indicates if the window can be docked by dragging it by its
+	// title bar onto a drop target and if other windows can be docked with it.
+	IsDockable bool
+
 	// IsScrollable indicates if the window should scroll the contents based
 	// on mouse scroll wheel input.
 	IsScrollable bool
@@ -49,6 +101,10 @@ type Window struct {
 	// adjusted to accommodate all of the widgets.
 	AutoAdjustHeight bool
 
+	// AutoAdjustWidth indicates if the window's width should be automatically
+	// adjusted to accommodate the widest row of widgets.
+	AutoAdjustWidth bool
+
 	// Title is the string to display in the title bar if it is visible
 	Title string
 
@@ -57,6 +113,10 @@ type Window struct {
 	// to build the window's widgets.
 	OnBuild BuildCallback
 
+	// MenuBar, if set, gets called after OnBuild to build the menus of a menu
+	// bar drawn below the title bar with calls to Menu().
+	MenuBar BuildCallback
+
 	// Owner is the owning UI Manager object.
 	Owner *Manager
 
@@ -64,6 +124,10 @@ type Window struct {
 	// window hot to offset the controlls to give the scrolling effect.
 	ScrollOffset float32
 
+	// ScrollOffsetX is the horizontal scroll bar position which tells the
+	// window how far to the left to move the widgets in display coordinates.
+	ScrollOffsetX float32
+
 	// Style is the set of visual parameters to use when drawing this window.
 	Style
 
@@ -84,6 +148,63 @@ type Window struct {
 	// next control to be at most a specific size.
 	requestedItemWidthMaxDC float32
 
+	// contentWidthDC is the widest the rows of widgets have been this frame,
+	// which is used to automatically adjust the window's width.
+	contentWidthDC float32
+
+	// lastItemRect is the area of the last widget built as [x,y,w,h] in
+	// display coordinates, including its margins.
+	lastItemRect mgl.Vec4
+
+	// buildingMenuBar is true while MenuBar is being called.
+	buildingMenuBar bool
+
+	// menuBarCursorDC is the location of the top-left corner of the next menu
+	// in the menu bar in display coordinates.
+	menuBarCursorDC mgl.Vec2
+
+	// menuBarOpen is true if one of the menus of the menu bar was open last
+	// frame, which makes hovering the other menus open them.
+	menuBarOpen bool
+
+	// resizeEdges are the edges being dragged to resize the window as a mask
+	// of the resizeEdge* constants.
+	resizeEdges int
+
+	// scrollGrab is the scroll offset from when the cursor of a scroll bar
+	// was pressed, which the cursor is dragged relative to.
+	scrollGrab float32
+
+	// resizeGrab is the distance from where the mouse was pressed to the
+	// dragged edges in display coordinates.
+	resizeGrab mgl.Vec2
+
+	// clipStack is the stack of clip rectangles pushed with pushClipRect() as
+	// [x,y,w,h] in display coordinates.
+	clipStack []mgl.Vec4
+
+	// childStack is the stack of child regions started with BeginChild() that
+	// haven't been ended yet.
+	childStack []*childState
+
+	// tableStack is the stack of tables started with BeginTable() or
+	// Columns() that haven't been ended yet.
+	tableStack []*tableState
+
+	// dockNode is the leaf dock node the window is docked in or nil if the
+	// window is floating.
+	dockNode *DockNode
+
+	// hidden is true if the window is docked in a node that shows another
+	// window, so it isn't built or drawn.
+	hidden bool
+
+	// floatingLocation, floatingWidth and floatingHeight are where the window
+	// was before it was docked so that undocking can put it back.
+	floatingLocation mgl.Vec3
+	floatingWidth    float32
+	floatingHeight   float32
+
 	// indentLevel is the number of indents that each row should start off with.
 	// this means the new row should have a widgetCursorDC that is offset the
 	// amount of (Style.IndentSpacing * indentLevel).
@@ -110,7 +231,9 @@ func newWindow(id string, x, y, w, h float32, constructor BuildCallback) *Window
 	wnd.Height = h
 	wnd.OnBuild = constructor
 	wnd.ShowTitleBar = true
+	wnd.IsOpen = true
 	wnd.IsMoveable = true
+	wnd.IsDockable = true
 	//wnd.IsScrollable = false
 	wnd.Style = DefaultStyle
 	return wnd
@@ -121,68 +244,137 @@ func newWindow(id string, x, y, w, h float32, constructor BuildCallback) *Window
 func (wnd *Window) construct() {
 	// empty out the cmd list and start a new command
 	wnd.cmds = wnd.cmds[:0]
+	wnd.clipStack = wnd.clipStack[:0]
+	wnd.childStack = wnd.childStack[:0]
+	wnd.tableStack = wnd.tableStack[:0]
 
-	mouseX, mouseY := wnd.Owner.GetMousePosition()
-	mouseDeltaX, mouseDeltaY := wnd.Owner.GetMousePositionDelta()
-	lmbDown := wnd.Owner.GetMouseButtonAction(0) == MouseDown
+	// dragging the edges of the window resizes it before anything is built
+	wnd.resizeBehavior()
+
+	// the buttons in the title bar come next; closing the window stops here
+	wnd.titleBarBehavior()
+	if !wnd.IsOpen {
+		return
+	}
+
+	// collapsed windows only get their title bar
+	if wnd.isCollapsed() {
+		wnd.buildFrame(0.0, 0.0)
+		wnd.moveBehavior()
+		return
+	}
+
+	mouseX, mouseY := wnd.getMousePosition()
 
 	// if the mouse is in the window, then let's scroll if the scroll input
-	// was received.
-	if wnd.IsScrollable && wnd.ContainsPosition(mouseX, mouseY) {
-		wnd.ScrollOffset -= wnd.Owner.GetScrollWheelDelta(true)
-		if wnd.ScrollOffset < 0.0 {
-			wnd.ScrollOffset = 0.0
+	// was received. holding shift scrolls sideways instead.
+	if wnd.IsScrollable && wnd.ContainsPosition(mouseX, mouseY) && !wnd.Owner.wheelCapturedLastFrame {
+		if wnd.Owner.isShiftDown() {
+			wnd.ScrollOffsetX -= wnd.Owner.GetScrollWheelDelta(true)
+			if wnd.ScrollOffsetX < 0.0 {
+				wnd.ScrollOffsetX = 0.0
+			}
+		} else {
+			wnd.ScrollOffset -= wnd.Owner.GetScrollWheelDelta(true)
+			if wnd.ScrollOffset < 0.0 {
+				wnd.ScrollOffset = 0.0
+			}
 		}
 	}
 
 	// reset the cursor for the window
 	wnd.widgetCursorDC = mgl.Vec3{wnd.Style.WindowPadding[0], wnd.ScrollOffset, 0}
 	wnd.nextRowCursorOffsetDC = 0
+	wnd.contentWidthDC = 0
 
 	// advance the cursor to account for the title bar
-	_, _, _, frameHeight := wnd.GetFrameSize()
-	_, _, _, displayHeight := wnd.GetDisplaySize()
-	wnd.widgetCursorDC[1] = wnd.widgetCursorDC[1] - (frameHeight - displayHeight) - wnd.WindowPadding[2]
+	wnd.widgetCursorDC[1] = wnd.widgetCursorDC[1] - wnd.titleBarHeight() - wnd.menuBarHeight() - wnd.WindowPadding[2]
 
 	// invoke the callback to build the widgets for the window
 	if wnd.OnBuild != nil {
 		wnd.OnBuild(wnd)
 	}
 
+	// end any child regions and tables the callback left open
+	wnd.endLayoutsTo(0)
+
 	// calculate the height all of the controls would need to draw. this can be
 	// used to automatically resize the window and will be used to draw a correctly
 	// proportioned scroll bar cursor.
 	totalControlHeightDC := -wnd.widgetCursorDC[1] + wnd.nextRowCursorOffsetDC + wnd.ScrollOffset + wnd.WindowPadding[3]
 	_, totalControlHeightS := wnd.Owner.DisplayToScreen(0.0, totalControlHeightDC)
+	totalControlWidthDC := wnd.contentWidthDC + wnd.WindowPadding[1]
 
 	// are we going to fit the height of the window to the height of the controls?
-	if wnd.AutoAdjustHeight {
+	// docked windows get their size from the dock node instead.
+	if wnd.AutoAdjustHeight && wnd.dockNode == nil {
 		wnd.Height = totalControlHeightS
 	}
+	if wnd.AutoAdjustWidth && wnd.dockNode == nil {
+		wnd.Width, _ = wnd.Owner.DisplayToScreen(wnd.contentWidthDC+wnd.WindowPadding[1], 0.0)
+	}
+	if wnd.dockNode == nil {
+		wnd.clampSize()
+	}
+
+	// the keyboard and the scroll bars can scroll the window too
+	wnd.scrollKeyBehavior(totalControlHeightDC)
+	wnd.scrollBarBehavior(totalControlHeightDC, totalControlWidthDC)
 
 	// do we need to roll back the scroll bar change? has it overextended the
 	// bounds and need to be pulled back in? make sure that the total control
 	// height is actually greter than display height and requires scrolling first.
+	// the size may have changed above, so get the display size again.
+	_, _, displayWidth, displayHeight := wnd.GetDisplaySize()
 	controlHeightOverflow := totalControlHeightDC - displayHeight
 	if wnd.IsScrollable && controlHeightOverflow > 0 && wnd.ScrollOffset > controlHeightOverflow {
 		wnd.ScrollOffset = controlHeightOverflow
-	} else if controlHeightOverflow < 0 {
+	} else if controlHeightOverflow < 0 || wnd.ScrollOffset < 0 {
 		// more space then needed so reset the scroll bar
 		wnd.ScrollOffset = 0
 	}
 
+	// the same goes for scrolling sideways past the widest row of widgets
+	controlWidthOverflow := totalControlWidthDC - displayWidth
+	if wnd.IsScrollable && controlWidthOverflow > 0 && wnd.ScrollOffsetX > controlWidthOverflow {
+		wnd.ScrollOffsetX = controlWidthOverflow
+	} else if controlWidthOverflow < 0 || wnd.ScrollOffsetX < 0 {
+		wnd.ScrollOffsetX = 0
+	}
+
+	// build the menu bar on top of the widgets
+	if wnd.MenuBar != nil {
+		wnd.buildMenuBar()
+	}
+
 	// build the frame background for the window including title bar and scroll bar.
-	wnd.buildFrame(totalControlHeightDC)
+	wnd.buildFrame(totalControlHeightDC, totalControlWidthDC)
 
 	// next frame we potientially will have a different window location
-	// do we need to move the window? (LMB down in a window and mouse dragged)
-	if wnd.IsMoveable && lmbDown && wnd.ContainsPosition(mouseX, mouseY) {
+	wnd.moveBehavior()
+}
+
+// moveBehavior moves the window while the left mouse button is dragged in it.
+// Docked windows don't move, but they can be undocked with the title bar.
+func (wnd *Window) moveBehavior() {
+	mouseX, mouseY := wnd.getMousePosition()
+	mouseDeltaX, mouseDeltaY := wnd.Owner.GetMousePositionDelta()
+	lmbDown := wnd.Owner.GetMouseButtonAction(0) == MouseDown
+
+	if wnd.dockNode != nil {
+		wnd.dockBehavior()
+	} else if wnd.IsMoveable && lmbDown && wnd.ContainsPosition(mouseX, mouseY) {
 		claimed := wnd.Owner.SetActiveInputID(wnd.ID)
 		if claimed || wnd.Owner.GetActiveInputID() == wnd.ID {
 			// mouse down in the window, lets move the thing before we make the vertices
 			deltaXS, deltaYS := wnd.Owner.DisplayToScreen(mouseDeltaX, mouseDeltaY)
 			wnd.Location[0] += deltaXS
 			wnd.Location[1] += deltaYS
+
+			// dragging the window by the title bar shows where it can be docked
+			if wnd.IsDockable && wnd.titleBarContains(wnd.getMouseDownPosition(0)) {
+				wnd.Owner.dockDragWindow = wnd
+			}
 		}
 	}
 }
@@ -190,7 +382,19 @@ func (wnd *Window) construct() {
 // GetDisplaySize returns four values: the x and y positions of the window
 // on the screen in display-space and then the width and height of the window
 // in display-space values. This does not include space for the scroll bars.
+// Between BeginChild() and EndChild() it returns the area inside of the child
+// region instead.
 func (wnd *Window) GetDisplaySize() (float32, float32, float32, float32) {
+	if child := wnd.currentChild(); child != nil {
+		r := child.contentRect
+		return r[0], r[1], r[2], r[3]
+	}
+	return wnd.windowDisplaySize()
+}
+
+// windowDisplaySize returns the location and size of the window like
+// GetDisplaySize() does, even inside of a child region.
+func (wnd *Window) windowDisplaySize() (float32, float32, float32, float32) {
 	winxDC, winyDC := wnd.Owner.ScreenToDisplay(wnd.Location[0], wnd.Location[1])
 	winwDC, winhDC := wnd.Owner.ScreenToDisplay(wnd.Width, wnd.Height)
 
@@ -206,25 +410,122 @@ func (wnd *Window) GetAspectRatio() float32 {
 // coordinates and the width and height of the total window frame as well, including
 // the space window decorations take up like titlebar and scrollbar.
 func (wnd *Window) GetFrameSize() (float32, float32, float32, float32) {
-	winxDC, winyDC, winwDC, winhDC := wnd.GetDisplaySize()
+	winxDC, winyDC, winwDC, winhDC := wnd.windowDisplaySize()
 
 	// add in the size of the scroll bar if we're going to show it
 	if wnd.ShowScrollBar {
 		winwDC += wnd.Style.ScrollBarWidth
 	}
 
-	// add the size of the title bar if it's visible
-	if wnd.ShowTitleBar {
-		font := wnd.Owner.GetFont(wnd.Style.FontName)
-		if font != nil {
-			_, dimY, _ := font.GetRenderSize(wnd.GetTitleString())
-			// TODO: for now just add 1 pixel on each side of the string for padding
-			winhDC += float32(dimY) + wnd.Style.TitleBarPadding[2] + wnd.Style.TitleBarPadding[3]
-		}
+	// add the size of the title bar if it's visible, the menu bar if there
+	// is one and the horizontal scroll bar; collapsed windows are just the
+	// title bar.
+	if wnd.isCollapsed() {
+		winhDC = wnd.titleBarHeight()
+	} else {
+		winhDC += wnd.titleBarHeight() + wnd.menuBarHeight() + wnd.horizontalScrollBarHeight()
 	}
 	return winxDC, winyDC, winwDC, winhDC
 }
 
+// horizontalScrollBarHeight returns the height of the horizontal scroll bar
+// in display coordinates or zero if it's not visible.
+func (wnd *Window) horizontalScrollBarHeight() float32 {
+	if !wnd.ShowHorizontalScrollBar {
+		return 0.0
+	}
+	return wnd.Style.ScrollBarWidth
+}
+
+// isCollapsed returns true if only the title bar of the window is shown.
+// Docked windows and windows without a title bar don't collapse.
+func (wnd *Window) isCollapsed() bool {
+	return wnd.IsCollapsed && wnd.ShowTitleBar && wnd.dockNode == nil
+}
+
+// isShown returns true if the window is open and isn't hidden behind another
+// window docked as a tab in the same dock node.
+func (wnd *Window) isShown() bool {
+	return wnd.IsOpen && !wnd.hidden
+}
+
+// hasCollapseButton returns true if the collapse arrow is in the title bar.
+func (wnd *Window) hasCollapseButton() bool {
+	return wnd.IsCollapsible && wnd.ShowTitleBar && wnd.dockNode == nil
+}
+
+// hasCloseButton returns true if the close button is in the title bar. It's
+// left out when the title bar shows the tabs of docked windows.
+func (wnd *Window) hasCloseButton() bool {
+	return wnd.ShowCloseButton && wnd.ShowTitleBar && len(wnd.dockTabWidths()) == 0
+}
+
+// titleBarButtonRects returns the areas of the collapse arrow and the close
+// button in the title bar as [x,y,w,h] in display coordinates.
+func (wnd *Window) titleBarButtonRects() (mgl.Vec4, mgl.Vec4) {
+	x, y, w, _ := wnd.GetFrameSize()
+	padding := wnd.Style.TitleBarPadding
+	size := wnd.titleBarHeight() - padding[2] - padding[3]
+	collapseRect := mgl.Vec4{x + padding[0], y - padding[2], size, size}
+	closeRect := mgl.Vec4{x + w - padding[1] - size, y - padding[2], size, size}
+	return collapseRect, closeRect
+}
+
+// titleBarBehavior handles the collapse arrow and the close button in the
+// title bar as well as double-clicking the title bar to collapse the window.
+// The buttons only act if the mouse was pressed on them.
+func (wnd *Window) titleBarBehavior() {
+	collapseRect, closeRect := wnd.titleBarButtonRects()
+	mdx, mdy := wnd.getMouseDownPosition(0)
+	pressedIn := func(r mgl.Vec4) bool {
+		return mdx > r[0] && mdy > r[1]-r[3] && mdx < r[0]+r[2] && mdy < r[1]
+	}
+
+	if wnd.hasCloseButton() {
+		r := closeRect
+		if wnd.buttonBehavior(wnd.ID+"#close", r[0], r[1], r[2], r[3]) == buttonPressed && pressedIn(r) {
+			wnd.IsOpen = false
+			wnd.Owner.UndockWindow(wnd)
+			if wnd.Owner.focusedWindow == wnd {
+				wnd.Owner.focusedWindow = nil
+			}
+			if wnd.OnClose != nil {
+				wnd.OnClose(wnd)
+			}
+			return
+		}
+	}
+
+	if wnd.hasCollapseButton() {
+		r := collapseRect
+		if wnd.buttonBehavior(wnd.ID+"#collapse", r[0], r[1], r[2], r[3]) == buttonPressed && pressedIn(r) {
+			wnd.IsCollapsed = !wnd.IsCollapsed
+			return
+		}
+
+		// the second click of a double-click on the arrow already toggled it
+		mx, my := wnd.getMousePosition()
+		if wnd.Owner.GetMouseButtonAction(0) == MouseDoubleClick && wnd.titleBarContains(mx, my) && mx > r[0]+r[2] {
+			wnd.IsCollapsed = !wnd.IsCollapsed
+		}
+	}
+}
+
+// titleBarHeight returns the height of the title bar in display coordinates
+// or zero if it's not visible.
+func (wnd *Window) titleBarHeight() float32 {
+	if !wnd.ShowTitleBar {
+		return 0.0
+	}
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return 0.0
+	}
+	_, dimY, _ := font.GetRenderSize(wnd.GetTitleString())
+	// TODO: for now just add 1 pixel on each side of the string for padding
+	return float32(dimY) + wnd.Style.TitleBarPadding[2] + wnd.Style.TitleBarPadding[3]
+}
+
 // GetTitleString will return a string with one space in it or the Title property
 // if the Title is not an empty string.
 func (wnd *Window) GetTitleString() string {
@@ -237,26 +538,24 @@ func (wnd *Window) GetTitleString() string {
 
 func (wnd *Window) makeCmdList() *cmdList {
 	// clip to the frame size which includes space for title bar and scroll bar
-	wx, wy, ww, wh := wnd.GetFrameSize()
+	// or to the pushed clip rectangle
 	cmdList := newCmdList()
-	cmdList.clipRect[0] = wx
-	cmdList.clipRect[1] = wy
-	cmdList.clipRect[2] = ww
-	cmdList.clipRect[3] = wh
+	cmdList.clipRect = wnd.currentClipRect()
 	return cmdList
 }
 
 // getFirstCmd will return the first non-custom cmdList; if the first cmdList
-// is custom, it makes a new one.
+// is custom, it makes a new one. The first cmdList holds the frame, so it's
+// always clipped to the whole frame.
 func (wnd *Window) getFirstCmd() *cmdList {
 	// empty list
 	if len(wnd.cmds) == 0 {
-		wnd.cmds = []*cmdList{wnd.makeCmdList()}
+		wnd.cmds = []*cmdList{wnd.makeFrameCmdList()}
 	}
 
 	// if the first cmd is custom, then insert a new one
 	if wnd.cmds[0].isCustom {
-		newCmd := wnd.makeCmdList()
+		newCmd := wnd.makeFrameCmdList()
 		newSlice := []*cmdList{}
 		newSlice = append(newSlice, newCmd)
 		newSlice = append(newSlice, wnd.cmds...)
@@ -266,6 +565,15 @@ func (wnd *Window) getFirstCmd() *cmdList {
 	return wnd.cmds[0]
 }
 
+// makeFrameCmdList makes a new cmdList clipped to the window frame even if a
+// clip rectangle was pushed.
+func (wnd *Window) makeFrameCmdList() *cmdList {
+	x, y, w, h := wnd.GetFrameSize()
+	cmdList := newCmdList()
+	cmdList.clipRect = mgl.Vec4{x, y, w, h}
+	return cmdList
+}
+
 // getLastCmd will return the last non-custom cmdList
 func (wnd *Window) getLastCmd() *cmdList {
 	// empty list
@@ -292,8 +600,79 @@ func (wnd *Window) addNewCmd() *cmdList {
 	return newCmd
 }
 
+// addClippedCmd adds a new cmdList that is clipped to the intersection of the
+// current clip rectangle and the rectangle with the top-left corner at (x,y)
+// and the size (w,h), all in display coordinates. Widgets that use it should
+// call addNewCmd() when done so that following widgets are not clipped.
+func (wnd *Window) addClippedCmd(x, y, w, h float32) *cmdList {
+	cmd := wnd.addNewCmd()
+	cmd.clipRect = intersectClipRects(cmd.clipRect, mgl.Vec4{x, y, w, h})
+	return cmd
+}
+
+// pushClipRect clips the widgets built after it to the intersection of the
+// current clip rectangle and the rectangle as [x,y,w,h] in display coordinates
+// until popClipRect() is called.
+func (wnd *Window) pushClipRect(r mgl.Vec4) {
+	// make sure the frame gets its own cmdList that isn't clipped
+	wnd.getFirstCmd()
+	wnd.clipStack = append(wnd.clipStack, intersectClipRects(wnd.currentClipRect(), r))
+	wnd.addNewCmd()
+}
+
+// popClipRect goes back to the clip rectangle from before the last call to
+// pushClipRect().
+func (wnd *Window) popClipRect() {
+	if len(wnd.clipStack) == 0 {
+		return
+	}
+	wnd.clipStack = wnd.clipStack[:len(wnd.clipStack)-1]
+	wnd.addNewCmd()
+}
+
+// currentClipRect returns the rectangle new cmdLists are clipped to as
+// [x,y,w,h] in display coordinates, which is the window frame unless a clip
+// rectangle was pushed.
+func (wnd *Window) currentClipRect() mgl.Vec4 {
+	if len(wnd.clipStack) > 0 {
+		return wnd.clipStack[len(wnd.clipStack)-1]
+	}
+	x, y, w, h := wnd.GetFrameSize()
+	return mgl.Vec4{x, y, w, h}
+}
+
+// intersectClipRects returns the intersection of the two rectangles given as
+// [x,y,w,h] in display coordinates with (x,y) being the top-left corner.
+func intersectClipRects(a, b mgl.Vec4) mgl.Vec4 {
+	left := b[0]
+	if a[0] > left {
+		left = a[0]
+	}
+	right := b[0] + b[2]
+	if a[0]+a[2] < right {
+		right = a[0] + a[2]
+	}
+	top := b[1]
+	if a[1] < top {
+		top = a[1]
+	}
+	bottom := b[1] - b[3]
+	if a[1]-a[3] > bottom {
+		bottom = a[1] - a[3]
+	}
+
+	r := mgl.Vec4{left, top, right - left, top - bottom}
+	if r[2] < 0.0 {
+		r[2] = 0.0
+	}
+	if r[3] < 0.0 {
+		r[3] = 0.0
+	}
+	return r
+}
+
 // buildFrame builds the background for the window
-func (wnd *Window) buildFrame(totalControlHeightDC float32) {
+func (wnd *Window) buildFrame(totalControlHeightDC, totalControlWidthDC float32) {
 	var combos []float32
 	var indexes []uint32
 	var fc uint32
@@ -303,19 +682,10 @@ func (wnd *Window) buildFrame(totalControlHeightDC float32) {
 
 	// get the dimensions for the window frame
 	x, y, w, h := wnd.GetFrameSize()
-	titleBarHeight := float32(0.0)
+	titleBarHeight := wnd.titleBarHeight()
 
 	// if we don't have a title bar, then simply render the background frame
 	if wnd.ShowTitleBar {
-		// how big should the title bar be?
-		titleString := " "
-		if len(wnd.Title) > 0 {
-			titleString = wnd.Title
-		}
-		font := wnd.Owner.GetFont(wnd.Style.FontName)
-		_, dimY, _ := font.GetRenderSize(titleString)
-
-		titleBarHeight = float32(dimY) + wnd.Style.TitleBarPadding[2] + wnd.Style.TitleBarPadding[3]
 		titleBarTextPos := mgl.Vec3{
 			x + wnd.Style.TitleBarPadding[0],
 			y - wnd.Style.TitleBarPadding[2],
@@ -325,12 +695,43 @@ func (wnd *Window) buildFrame(totalControlHeightDC float32) {
 		combos, indexes, fc = firstCmd.DrawRectFilledDC(x, y, x+w, y-titleBarHeight, wnd.Style.TitleBarBgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
 		firstCmd.AddFaces(combos, indexes, fc)
 
-		// render the title bar text
-		if len(wnd.Title) > 0 {
+		// render the collapse arrow and move the title text over for it
+		collapseRect, closeRect := wnd.titleBarButtonRects()
+		if wnd.hasCollapseButton() {
+			r := collapseRect
+			inset := r[2] * 0.25
+			combos, indexes, fc = firstCmd.drawTreeNodeIcon(!wnd.IsCollapsed, r[0]+inset, r[1]-inset, r[0]+r[2]-inset, r[1]-r[3]+inset, wnd.Style.TitleBarTextColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			firstCmd.AddFaces(combos, indexes, fc)
+			titleBarTextPos[0] += r[2] + wnd.Style.TitleBarPadding[0]
+		}
+
+		// render the close button with a highlight while the mouse is over it
+		if wnd.hasCloseButton() {
+			r := closeRect
+			mx, my := wnd.getMousePosition()
+			activeID := wnd.Owner.GetActiveInputID()
+			if mx > r[0] && my > r[1]-r[3] && mx < r[0]+r[2] && my < r[1] && (activeID == "" || activeID == wnd.ID+"#close") {
+				combos, indexes, fc = firstCmd.DrawRectFilledDC(r[0], r[1], r[0]+r[2], r[1]-r[3], wnd.Style.ButtonHoverColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+				firstCmd.AddFaces(combos, indexes, fc)
+			}
+			inset := r[2] * 0.2
+			combos, indexes, fc = firstCmd.drawCrossIcon(r[0]+inset, r[1]-inset, r[0]+r[2]-inset, r[1]-r[3]+inset, 2.0, wnd.Style.TitleBarTextColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			firstCmd.AddFaces(combos, indexes, fc)
+		}
+
+		// render the tabs of the windows docked with this one or the title bar text
+		if len(wnd.dockTabWidths()) > 0 {
+			wnd.buildDockTabs(firstCmd, x, y, titleBarHeight)
+		} else if font := wnd.Owner.GetFont(wnd.Style.FontName); font != nil && len(wnd.Title) > 0 {
 			renderData := font.CreateText(titleBarTextPos, wnd.Style.TitleBarTextColor, wnd.Title)
 			firstCmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
 		}
 
+		// collapsed windows are just the title bar
+		if wnd.isCollapsed() {
+			return
+		}
+
 		// render the rest of the window background
 		combos, indexes, fc = firstCmd.DrawRectFilledDC(x, y-titleBarHeight, x+w, y-h-titleBarHeight, wnd.Style.WindowBgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
 		firstCmd.PrefixFaces(combos, indexes, fc)
@@ -340,41 +741,327 @@ func (wnd *Window) buildFrame(totalControlHeightDC float32) {
 		firstCmd.PrefixFaces(combos, indexes, fc)
 	}
 
+	// the scroll bars and the resize grip go in a new cmdList after the
+	// widgets so that they get drawn on top of them
+	barCmd := firstCmd
+	if wnd.ShowScrollBar || wnd.ShowHorizontalScrollBar || wnd.resizeEdgesAllowed() != 0 {
+		barCmd = wnd.addNewCmd()
+	}
+
+	vTrack, hTrack := wnd.scrollBarTracks()
 	if wnd.ShowScrollBar {
-		// now add in the scroll bar at the end to overlay everything
-		sbX := x + w - wnd.Style.ScrollBarWidth
-		sbY := y - titleBarHeight
-		combos, indexes, fc = firstCmd.DrawRectFilledDC(sbX, sbY, x+w, y-h, wnd.Style.ScrollBarBgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
-		firstCmd.AddFaces(combos, indexes, fc)
+		wnd.drawScrollBar(barCmd, vTrack, true, totalControlHeightDC, wnd.ScrollOffset)
+	}
+	if wnd.ShowHorizontalScrollBar {
+		wnd.drawScrollBar(barCmd, hTrack, false, totalControlWidthDC, wnd.ScrollOffsetX)
+	}
 
-		// figure out the positioning
-		sbCursorWidth := wnd.Style.ScrollBarCursorWidth
-		if sbCursorWidth > wnd.Style.ScrollBarWidth {
-			sbCursorWidth = wnd.Style.ScrollBarWidth
+	// draw the resize grip in the bottom-right corner
+	if wnd.resizeEdgesAllowed()&(resizeEdgeRight|resizeEdgeBottom) == resizeEdgeRight|resizeEdgeBottom {
+		gripColor := wnd.Style.ResizeGripColor
+		mx, my := wnd.getMousePosition()
+		hovered := wnd.resizeEdgesAt(mx, my) == resizeEdgeRight|resizeEdgeBottom && wnd.Owner.GetActiveInputID() == ""
+		if hovered || wnd.resizeEdges == resizeEdgeRight|resizeEdgeBottom {
+			gripColor = wnd.Style.ResizeGripHoverColor
 		}
-		sbCursorOffX := (wnd.Style.ScrollBarWidth - sbCursorWidth) / 2.0
+		grip := wnd.Style.ResizeGripSize
+		combos, indexes, fc = barCmd.drawTriangleDC(x+w, y-h, x+w, y-h+grip, x+w-grip, y-h, gripColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+		barCmd.AddFaces(combos, indexes, fc)
+	}
+}
+
+// scrollBarTracks returns the areas of the vertical and horizontal scroll bars
+// as [x,y,w,h] in display coordinates. The vertical one runs along the right
+// side of the widgets and the horizontal one along the bottom.
+func (wnd *Window) scrollBarTracks() (mgl.Vec4, mgl.Vec4) {
+	x, y, w, h := wnd.GetFrameSize()
+	top := y - wnd.titleBarHeight() - wnd.menuBarHeight()
+	bottom := y - h + wnd.horizontalScrollBarHeight()
+	var vbarW float32
+	if wnd.ShowScrollBar {
+		vbarW = wnd.Style.ScrollBarWidth
+	}
+	vTrack := mgl.Vec4{x + w - vbarW, top, vbarW, top - bottom}
+	hTrack := mgl.Vec4{x, bottom, w - vbarW, wnd.horizontalScrollBarHeight()}
+	return vTrack, hTrack
+}
+
+// scrollBarCursor returns the distance of a scroll bar cursor from the start
+// of its track, the length of the cursor and the ratio of the track length to
+// the length of the content in display coordinates. If the content fits, the
+// cursor takes up the whole track.
+func scrollBarCursor(trackLen, contentLen, offset float32) (float32, float32, float32) {
+	ratio := trackLen / contentLen
+	if !(ratio < 1.0) || ratio <= 0.0 {
+		ratio = 1.0
+	}
+	return offset * ratio, trackLen * ratio, ratio
+}
+
+// scrollBarBehavior lets the cursors of the scroll bars be dragged and pages
+// the window when the mouse is pressed on a track outside of the cursor.
+func (wnd *Window) scrollBarBehavior(totalControlHeightDC, totalControlWidthDC float32) {
+	if !wnd.IsScrollable {
+		return
+	}
 
-		// calculate the height required for the scrollbar
-		sbUsableHeight := h - titleBarHeight
-		sbRatio := sbUsableHeight / totalControlHeightDC
+	vTrack, hTrack := wnd.scrollBarTracks()
+	_, _, displayWidth, displayHeight := wnd.GetDisplaySize()
+	if wnd.ShowScrollBar {
+		wnd.scrollBarTrackBehavior(wnd.ID+"#vscroll", vTrack, true, totalControlHeightDC, displayHeight, &wnd.ScrollOffset)
+	}
+	if wnd.ShowHorizontalScrollBar {
+		wnd.scrollBarTrackBehavior(wnd.ID+"#hscroll", hTrack, false, totalControlWidthDC, displayWidth, &wnd.ScrollOffsetX)
+	}
+}
 
-		// if we have more usable height than controls, just make the scrollbar
-		// take up the whole space.
-		if sbRatio >= 1.0 {
-			sbRatio = 1.0
+// scrollBarTrackBehavior lets the cursor of the scroll bar with the track as
+// [x,y,w,h] in display coordinates be dragged and changes the offset by a page
+// when the track is pressed outside of the cursor. The offset isn't clamped
+// to the length of the content.
+func (wnd *Window) scrollBarTrackBehavior(id string, track mgl.Vec4, vertical bool, contentLen, pageLen float32, offset *float32) {
+	ui := wnd.Owner
+	mdx, mdy := wnd.getMouseDownPosition(0)
+	mx, my := wnd.getMousePosition()
+
+	// measure along the track, which runs down for vertical scroll bars
+	trackStart, trackLen, mouseDown, mouse := track[0], track[2], mdx, mx
+	if vertical {
+		trackStart, trackLen, mouseDown, mouse = -track[1], track[3], -mdy, -my
+	}
+	cursorOff, cursorLen, ratio := scrollBarCursor(trackLen, contentLen, *offset)
+	cursorStart := trackStart + cursorOff
+
+	pressedIn := mdx > track[0] && mdy > track[1]-track[3] && mdx < track[0]+track[2] && mdy < track[1]
+	if ui.lmbPressed && pressedIn {
+		if mouseDown < cursorStart {
+			if ui.SetActiveInputID(id + "#track") {
+				*offset -= pageLen
+			}
+		} else if mouseDown > cursorStart+cursorLen {
+			if ui.SetActiveInputID(id + "#track") {
+				*offset += pageLen
+			}
+		} else if ui.SetActiveInputID(id) {
+			wnd.scrollGrab = *offset
 		}
+	}
+	if ui.GetMouseButtonAction(0) == MouseDown && ui.GetActiveInputID() == id {
+		*offset = wnd.scrollGrab + (mouse-mouseDown)/ratio
+	}
+}
 
-		sbCursorHeight := sbUsableHeight * sbRatio
+// drawScrollBar draws a scroll bar with the track as [x,y,w,h] in display
+// coordinates and the cursor placed for the offset into the content.
+func (wnd *Window) drawScrollBar(cmd *cmdList, track mgl.Vec4, vertical bool, contentLen, offset float32) {
+	combos, indexes, fc := cmd.DrawRectFilledDC(track[0], track[1], track[0]+track[2], track[1]-track[3], wnd.Style.ScrollBarBgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
 
-		// move the scroll bar down based on the scroll position
-		sbOffY := wnd.ScrollOffset * sbRatio
+	// the cursor is centered across the track
+	sbCursorWidth := wnd.Style.ScrollBarCursorWidth
+	if sbCursorWidth > wnd.Style.ScrollBarWidth {
+		sbCursorWidth = wnd.Style.ScrollBarWidth
+	}
 
-		// draw the scroll bar cursor
-		combos, indexes, fc = firstCmd.DrawRectFilledDC(sbX+sbCursorOffX, sbY-sbOffY, x+w-sbCursorOffX, sbY-sbOffY-sbCursorHeight, wnd.Style.ScrollBarCursorColor,
-			defaultTextureSampler, wnd.Owner.whitePixelUv)
-		firstCmd.AddFaces(combos, indexes, fc)
+	var x1, y1, x2, y2 float32
+	if vertical {
+		cursorOff, cursorLen, _ := scrollBarCursor(track[3], contentLen, offset)
+		inset := (track[2] - sbCursorWidth) / 2.0
+		x1, y1 = track[0]+inset, track[1]-cursorOff
+		x2, y2 = track[0]+track[2]-inset, track[1]-cursorOff-cursorLen
+	} else {
+		cursorOff, cursorLen, _ := scrollBarCursor(track[2], contentLen, offset)
+		inset := (track[3] - sbCursorWidth) / 2.0
+		x1, y1 = track[0]+cursorOff, track[1]-inset
+		x2, y2 = track[0]+cursorOff+cursorLen, track[1]-track[3]+inset
+	}
+	combos, indexes, fc = cmd.DrawRectFilledDC(x1, y1, x2, y2, wnd.Style.ScrollBarCursorColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+}
+
+// scrollKeyBehavior scrolls the focused window a page up or down with PageUp
+// and PageDown and to the top or bottom with Home and End if none of its
+// widgets used the keys.
+func (wnd *Window) scrollKeyBehavior(totalControlHeightDC float32) {
+	ui := wnd.Owner
+	if !wnd.IsScrollable || !wnd.IsFocused() {
+		return
+	}
+
+	_, _, _, displayHeight := wnd.GetDisplaySize()
+	if ui.consumeKey(EweyKeyPageUp) {
+		wnd.ScrollOffset -= displayHeight
+	}
+	if ui.consumeKey(EweyKeyPageDown) {
+		wnd.ScrollOffset += displayHeight
+	}
+	if ui.consumeKey(EweyKeyHome) {
+		wnd.ScrollOffset = 0.0
+	}
+	if ui.consumeKey(EweyKeyEnd) {
+		wnd.ScrollOffset = totalControlHeightDC - displayHeight
+	}
+}
+
+// these constants are the edges of a window that can be dragged to resize it
+const (
+	resizeEdgeLeft   = 1
+	resizeEdgeRight  = 2
+	resizeEdgeBottom = 4
+)
+
+// resizeEdgesAllowed returns the edges of the window that can be dragged to
+// resize it as a mask of the resizeEdge* constants. Docked windows get their
+// size from the dock node and automatically adjusted sizes can't be dragged.
+func (wnd *Window) resizeEdgesAllowed() int {
+	if !wnd.IsResizable || wnd.dockNode != nil || wnd.isCollapsed() {
+		return 0
+	}
+	edges := resizeEdgeLeft | resizeEdgeRight | resizeEdgeBottom
+	if wnd.AutoAdjustWidth {
+		edges &^= resizeEdgeLeft | resizeEdgeRight
+	}
+	if wnd.AutoAdjustHeight {
+		edges &^= resizeEdgeBottom
+	}
+	return edges
+}
+
+// resizeEdgesAt returns the edges of the window that can be dragged to resize
+// it at the display coordinate as a mask of the resizeEdge* constants.
+func (wnd *Window) resizeEdgesAt(x, y float32) int {
+	allowed := wnd.resizeEdgesAllowed()
+	if allowed == 0 || !wnd.ContainsPosition(x, y) {
+		return 0
+	}
+
+	fx, fy, fw, fh := wnd.GetFrameSize()
+	border := wnd.Style.ResizeBorderWidth
+	grip := wnd.Style.ResizeGripSize
+	edges := 0
+	if x > fx+fw-grip && y < fy-fh+grip {
+		edges = resizeEdgeRight | resizeEdgeBottom
+	}
+	if x < fx+border {
+		edges |= resizeEdgeLeft
+	}
+	if x > fx+fw-border {
+		edges |= resizeEdgeRight
+	}
+	if y < fy-fh+border {
+		edges |= resizeEdgeBottom
+	}
+	return edges & allowed
+}
+
+// resizeBehavior resizes the window while its edges are dragged and asks for
+// a resize cursor while the mouse is over them.
+func (wnd *Window) resizeBehavior() {
+	ui := wnd.Owner
+	resizeID := wnd.ID + "#resize"
+	fx, fy, fw, fh := wnd.GetFrameSize()
+
+	// pressing the mouse on an edge starts resizing the window
+	lmbDown := ui.GetMouseButtonAction(0) == MouseDown
+	if lmbDown && ui.GetActiveInputID() != resizeID {
+		mdx, mdy := wnd.getMouseDownPosition(0)
+		edges := wnd.resizeEdgesAt(mdx, mdy)
+		if edges != 0 && ui.SetActiveInputID(resizeID) {
+			wnd.resizeEdges = edges
+			wnd.resizeGrab[0] = fx + fw - mdx
+			if edges&resizeEdgeLeft != 0 {
+				wnd.resizeGrab[0] = fx - mdx
+			}
+			wnd.resizeGrab[1] = fy - fh - mdy
+		}
+	}
+	if !lmbDown || ui.GetActiveInputID() != resizeID {
+		wnd.resizeEdges = 0
+	}
+
+	// the cursor shows which edges can be dragged
+	mx, my := wnd.getMousePosition()
+	edges := wnd.resizeEdges
+	if edges == 0 && ui.GetActiveInputID() == "" {
+		edges = wnd.resizeEdgesAt(mx, my)
+	}
+	switch {
+	case edges&resizeEdgeBottom != 0 && edges&resizeEdgeRight != 0:
+		ui.cursorShape = CursorResizeNWSE
+	case edges&resizeEdgeBottom != 0 && edges&resizeEdgeLeft != 0:
+		ui.cursorShape = CursorResizeNESW
+	case edges&resizeEdgeBottom != 0:
+		ui.cursorShape = CursorResizeNS
+	case edges != 0:
+		ui.cursorShape = CursorResizeEW
+	}
+
+	if wnd.resizeEdges == 0 {
+		return
+	}
+
+	// the frame includes the scroll bar, title bar and menu bar, but the
+	// window's size doesn't
+	var scrollBarW float32
+	if wnd.ShowScrollBar {
+		scrollBarW = wnd.Style.ScrollBarWidth
+	}
+	left, right := fx, fx+fw
+	if wnd.resizeEdges&resizeEdgeLeft != 0 {
+		left = mx + wnd.resizeGrab[0]
+	}
+	if wnd.resizeEdges&resizeEdgeRight != 0 {
+		right = mx + wnd.resizeGrab[0]
+	}
+	decorationH := wnd.titleBarHeight() + wnd.menuBarHeight() + wnd.horizontalScrollBarHeight()
+	widthDC := right - left - scrollBarW
+	heightDC := fh - decorationH
+	if wnd.resizeEdges&resizeEdgeBottom != 0 {
+		heightDC = fy - (my + wnd.resizeGrab[1]) - decorationH
+	}
+
+	width, height := ui.DisplayToScreen(widthDC, heightDC)
+	width, height = wnd.clampedSize(width, height)
+	if wnd.resizeEdges&resizeEdgeLeft != 0 {
+		// keep the right edge in place
+		widthDC, _ = ui.ScreenToDisplay(width, 0.0)
+		wnd.Location[0], _ = ui.DisplayToScreen(right-scrollBarW-widthDC, 0.0)
+	}
+	wnd.Width, wnd.Height = width, height
+}
 
+// clampedSize returns the width and height limited by MinSize and MaxSize.
+func (wnd *Window) clampedSize(width, height float32) (float32, float32) {
+	if wnd.MaxSize[0] > 0.0 && width > wnd.MaxSize[0] {
+		width = wnd.MaxSize[0]
+	}
+	if wnd.MaxSize[1] > 0.0 && height > wnd.MaxSize[1] {
+		height = wnd.MaxSize[1]
+	}
+	if width < wnd.MinSize[0] {
+		width = wnd.MinSize[0]
+	}
+	if height < wnd.MinSize[1] {
+		height = wnd.MinSize[1]
 	}
+	if width < 0.0 {
+		width = 0.0
+	}
+	if height < 0.0 {
+		height = 0.0
+	}
+	return width, height
+}
+
+// clampSize keeps the size of the window within MinSize and MaxSize.
+func (wnd *Window) clampSize() {
+	wnd.Width, wnd.Height = wnd.clampedSize(wnd.Width, wnd.Height)
+}
+
+// IsFocused returns true if the window was the last one clicked or was
+// focused with Manager.SetFocusedWindow().
+func (wnd *Window) IsFocused() bool {
+	return wnd.Owner.focusedWindow == wnd
 }
 
 // ContainsPosition returns true if the position passed in is contained within
@@ -387,6 +1074,50 @@ func (wnd *Window) ContainsPosition(x, y float32) bool {
 	return false
 }
 
+// getMousePosition returns the mouse position if the window gets mouse input
+// this frame, otherwise it returns a position outside of the user interface
+// so that the widgets under other windows don't react to the mouse. While a
+// clip rectangle is pushed, the mouse has to be inside of it as well unless
+// the left mouse button was pressed inside of it and is still down, so that
+// widgets being dragged keep following the mouse.
+func (wnd *Window) getMousePosition() (float32, float32) {
+	if wnd.Owner.mouseOwner != wnd {
+		return -1.0, -1.0
+	}
+	mx, my := wnd.Owner.GetMousePosition()
+	if !wnd.clipContains(mx, my) {
+		mdx, mdy := wnd.Owner.GetMouseDownPosition(0)
+		if wnd.Owner.GetMouseButtonAction(0) != MouseDown || !wnd.clipContains(mdx, mdy) {
+			return -1.0, -1.0
+		}
+	}
+	return mx, my
+}
+
+// getMouseDownPosition returns the position the mouse button was pressed at
+// if the window gets mouse input this frame and the press was inside of the
+// pushed clip rectangle, if any; see getMousePosition().
+func (wnd *Window) getMouseDownPosition(button int) (float32, float32) {
+	if wnd.Owner.mouseOwner != wnd {
+		return -1.0, -1.0
+	}
+	mdx, mdy := wnd.Owner.GetMouseDownPosition(button)
+	if !wnd.clipContains(mdx, mdy) {
+		return -1.0, -1.0
+	}
+	return mdx, mdy
+}
+
+// clipContains returns true if no clip rectangle is pushed or the display
+// coordinate is inside of the pushed one.
+func (wnd *Window) clipContains(x, y float32) bool {
+	if len(wnd.clipStack) == 0 {
+		return true
+	}
+	r := wnd.clipStack[len(wnd.clipStack)-1]
+	return x > r[0] && y > r[1]-r[3] && x < r[0]+r[2] && y < r[1]
+}
+
 // StartRow starts a new row of widgets in the window.
 func (wnd *Window) StartRow() {
 	// adjust the widgetCursor if necessary to start a new row.
@@ -403,9 +1134,16 @@ func (wnd *Window) getCursorDC() mgl.Vec3 {
 	// start with the widget DC offet
 	pos := wnd.widgetCursorDC
 
-	// add in the position of the window in pixels
+	// inside of a child region the cursor is relative to the child
+	if child := wnd.currentChild(); child != nil {
+		pos[0] += child.contentRect[0] - child.scrollOffsetX
+		pos[1] += child.contentRect[1]
+		return pos
+	}
+
+	// add in the position of the window in pixels and scroll it sideways
 	windowDx, windowDy := wnd.Owner.ScreenToDisplay(wnd.Location[0], wnd.Location[1])
-	pos[0] += windowDx
+	pos[0] += windowDx - wnd.ScrollOffsetX
 	pos[1] += windowDy
 
 	return pos
@@ -472,12 +1210,21 @@ func (wnd *Window) addCursorHorizontalDelta(hWidth float32) {
 		wnd.requestedItemWidthMaxDC = 0.0
 	}
 
+	// remember where the widget was so that it can be referred to afterwards
+	pos := wnd.getCursorDC()
+	wnd.lastItemRect = mgl.Vec4{pos[0], pos[1], hWidth, 0.0}
+
 	wnd.widgetCursorDC[0] += hWidth
+	if wnd.widgetCursorDC[0] > wnd.contentWidthDC {
+		wnd.contentWidthDC = wnd.widgetCursorDC[0]
+	}
 }
 
 // setNextRowCursorOffset specifies how much to change the cursor position
 // when a new row is started.
 func (wnd *Window) setNextRowCursorOffset(offset float32) {
+	wnd.lastItemRect[3] = offset
+
 	// only set the next row offset if the one being passed in is greater
 	// than the offset recorded by other widgets
 	if offset > wnd.nextRowCursorOffsetDC {
@@ -500,6 +1247,17 @@ func (wnd *Window) clampWidgetWidthToReqW(widthDC float32) float32 {
 	return result
 }
 
+// takeRowWidth returns the width left in the current row for the next widget,
+// clamped to the requested min and max values, and then clears the requests
+// so that widgets made of several smaller widgets can size the parts themselves.
+func (wnd *Window) takeRowWidth() float32 {
+	_, _, wndWidth, _ := wnd.GetDisplaySize()
+	widthDC := wnd.clampWidgetWidthToReqW(wndWidth - wnd.widgetCursorDC[0] - wnd.Style.WindowPadding[1])
+	wnd.requestedItemWidthMinDC = 0.0
+	wnd.requestedItemWidthMaxDC = 0.0
+	return widthDC
+}
+
 // Indent increases the indent level in the window, which also immediately changes
 // the widgetCursorDC value.
 func (wnd *Window) Indent() {
@@ -588,16 +1346,22 @@ func (wnd *Window) Checkbox(id string, value *bool) (bool, error) {
 	bgColor := wnd.Style.CheckboxColor
 	pressed := false
 
+	// keyboard activation toggles the checkbox just like a click
+	_, activated := wnd.focusBehavior(id)
+
 	// test to see if the mouse is inside the widget
 	buttonTest := wnd.buttonBehavior(id, pos[0], pos[1], checkW, checkH)
 	if buttonTest == buttonPressed {
 		pressed = true
+	}
+	if pressed || activated {
 		*value = !(*value)
 	}
 
 	// render the widget background
 	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+checkW, pos[1]-checkH, bgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
 	cmd.AddFaces(combos, indexes, fc)
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], checkW, checkH)
 
 	// do we show the check in the checkbox
 	if *value {
@@ -621,7 +1385,7 @@ func (wnd *Window) Checkbox(id string, value *bool) (bool, error) {
 		wnd.Owner.ClearMouseButtonAction(0)
 	}
 
-	return pressed, nil
+	return pressed || activated, nil
 }
 
 // Button draws the button widget on screen with the given text.
@@ -652,6 +1416,9 @@ func (wnd *Window) Button(id string, text string) (bool, error) {
 	bgColor := wnd.Style.ButtonColor
 	pressed := false
 
+	// keyboard activation presses the button just like a click
+	_, activated := wnd.focusBehavior(id)
+
 	// test to see if the mouse is inside the widget
 	buttonTest := wnd.buttonBehavior(id, pos[0], pos[1], buttonW, buttonH)
 	if buttonTest == buttonPressed {
@@ -663,6 +1430,7 @@ func (wnd *Window) Button(id string, text string) (bool, error) {
 	// render the button background
 	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+buttonW, pos[1]-buttonH, bgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
 	cmd.AddFaces(combos, indexes, fc)
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], buttonW, buttonH)
 
 	// create the text for the button
 	centerTextX := (buttonW - dimX) / 2.0
@@ -683,7 +1451,7 @@ func (wnd *Window) Button(id string, text string) (bool, error) {
 		wnd.Owner.ClearMouseButtonAction(0)
 	}
 
-	return pressed, nil
+	return pressed || activated, nil
 }
 
 // SliderFloat creates a slider widget that alters a value based on the min/max
@@ -707,12 +1475,17 @@ func (wnd *Window) SliderFloat(id string, value *float32, min, max float32) erro
 		*value = tmp
 	}
 
+	// the arrow keys move the value by a hundredth of the range
+	if nudge := wnd.focusNudge(id); nudge != 0 {
+		*value = ClipF32(min, max, *value+float32(nudge)*(max-min)/100.0)
+	}
+
 	// get the position / size for the slider
 	cursorRel := *value
 	cursorRel = (cursorRel - min) / (max - min)
 
 	valueString = fmt.Sprintf(wnd.Style.SliderFloatFormat, *value)
-	return wnd.sliderBehavior(valueString, cursorRel, true)
+	return wnd.sliderBehavior(id, valueString, cursorRel, true)
 }
 
 // SliderInt creates a slider widget that alters a value based on the min/max
@@ -736,11 +1509,22 @@ func (wnd *Window) SliderInt(id string, value *int, min, max int) error {
 		*value = tmp
 	}
 
+	// the arrow keys move the value one step at a time
+	if nudge := wnd.focusNudge(id); nudge != 0 {
+		tmp := *value + nudge
+		if tmp > max {
+			tmp = max
+		} else if tmp < min {
+			tmp = min
+		}
+		*value = tmp
+	}
+
 	// get the position / size for the slider
 	cursorRel := float32(*value-min) / float32(max-min)
 
 	valueString = fmt.Sprintf(wnd.Style.SliderIntFormat, *value)
-	return wnd.sliderBehavior(valueString, cursorRel, true)
+	return wnd.sliderBehavior(id, valueString, cursorRel, true)
 }
 
 // DragSliderInt creates a slider widget that alters a value based on mouse
@@ -756,8 +1540,11 @@ func (wnd *Window) DragSliderInt(id string, speed float32, value *int) error {
 		*value += int(mouseDeltaX * speed)
 	}
 
+	// the arrow keys move the value one step at a time
+	*value += wnd.focusNudge(id)
+
 	valueString = fmt.Sprintf(wnd.Style.SliderIntFormat, *value)
-	return wnd.sliderBehavior(valueString, 0.0, false)
+	return wnd.sliderBehavior(id, valueString, 0.0, false)
 }
 
 // DragSliderUInt creates a slider widget that alters a value based on mouse
@@ -775,8 +1562,17 @@ func (wnd *Window) DragSliderUInt(id string, speed float32, value *uint) error {
 		}
 	}
 
-	valueString = fmt.Sprintf(wnd.Style.SliderIntFormat, *value)
-	return wnd.sliderBehavior(valueString, 0.0, false)
+	// the arrow keys move the value one step at a time
+	if nudge := wnd.focusNudge(id); nudge != 0 {
+		if nudge+int(*value) >= 0 {
+			*value = uint(int(*value) + nudge)
+		} else {
+			*value = 0
+		}
+	}
+
+	valueString = fmt.Sprintf(wnd.Style.SliderIntFormat, *value)
+	return wnd.sliderBehavior(id, valueString, 0.0, false)
 }
 
 // DragSliderFloat creates a slider widget that alters a value based on mouse
@@ -792,8 +1588,11 @@ func (wnd *Window) DragSliderFloat(id string, speed float32, value *float32) err
 		*value += mouseDeltaX * speed
 	}
 
+	// the arrow keys move the value as if the mouse moved a pixel
+	*value += float32(wnd.focusNudge(id)) * speed
+
 	valueString = fmt.Sprintf(wnd.Style.SliderFloatFormat, *value)
-	return wnd.sliderBehavior(valueString, 0.0, false)
+	return wnd.sliderBehavior(id, valueString, 0.0, false)
 }
 
 // DragSliderUFloat creates a slider widget that alters a value based on mouse
@@ -812,8 +1611,16 @@ func (wnd *Window) DragSliderUFloat(id string, speed float32, value *float32) er
 		}
 	}
 
+	// the arrow keys move the value as if the mouse moved a pixel
+	if nudge := wnd.focusNudge(id); nudge != 0 {
+		*value += float32(nudge) * speed
+		if *value < 0.0 {
+			*value = 0.0
+		}
+	}
+
 	valueString = fmt.Sprintf(wnd.Style.SliderFloatFormat, *value)
-	return wnd.sliderBehavior(valueString, 0.0, false)
+	return wnd.sliderBehavior(id, valueString, 0.0, false)
 }
 
 // DragSliderFloat64 creates a slider widget that alters a value based on mouse
@@ -829,8 +1636,11 @@ func (wnd *Window) DragSliderFloat64(id string, speed float64, value *float64) e
 		*value += float64(mouseDeltaX) * speed
 	}
 
+	// the arrow keys move the value as if the mouse moved a pixel
+	*value += float64(wnd.focusNudge(id)) * speed
+
 	valueString = fmt.Sprintf(wnd.Style.SliderFloatFormat, *value)
-	return wnd.sliderBehavior(valueString, 0.0, false)
+	return wnd.sliderBehavior(id, valueString, 0.0, false)
 }
 
 // DragSliderUFloat64 creates a slider widget that alters a value based on mouse
@@ -849,8 +1659,399 @@ func (wnd *Window) DragSliderUFloat64(id string, speed float64, value *float64)
 		}
 	}
 
+	// the arrow keys move the value as if the mouse moved a pixel
+	if nudge := wnd.focusNudge(id); nudge != 0 {
+		*value += float64(nudge) * speed
+		if *value < 0.0 {
+			*value = 0.0
+		}
+	}
+
 	valueString = fmt.Sprintf(wnd.Style.SliderFloatFormat, *value)
-	return wnd.sliderBehavior(valueString, 0.0, false)
+	return wnd.sliderBehavior(id, valueString, 0.0, false)
+}
+
+// InputInt creates a widget that shows an int value which can be changed by
+// dragging the mouse, with the arrow keys or by double clicking it to type in a
+// new value. Typed values are applied when Enter is pressed and discarded with
+// Escape. The value is clamped to [min .. max] unless min is not less than max.
+// If step is greater than 0, buttons to decrease and increase the value by step
+// are drawn beside the field. Returns true if the value was changed.
+func (wnd *Window) InputInt(id string, value *int, min, max, step int) (bool, error) {
+	format := func(v float64) string { return fmt.Sprintf(wnd.Style.SliderIntFormat, int(v)) }
+	newValue, changed, err := wnd.inputNumber(id, float64(*value), float64(min), float64(max), float64(step), true, format)
+	*value = int(newValue)
+	return changed, err
+}
+
+// InputFloat creates a widget that shows a float32 value which can be changed by
+// dragging the mouse, with the arrow keys or by double clicking it to type in a
+// new value. See InputInt() for details on the parameters.
+func (wnd *Window) InputFloat(id string, value *float32, min, max, step float32) (bool, error) {
+	format := func(v float64) string { return fmt.Sprintf(wnd.Style.SliderFloatFormat, float32(v)) }
+	newValue, changed, err := wnd.inputNumber(id, float64(*value), float64(min), float64(max), float64(step), false, format)
+	*value = float32(newValue)
+	return changed, err
+}
+
+// InputFloat64 creates a widget that shows a float64 value which can be changed by
+// dragging the mouse, with the arrow keys or by double clicking it to type in a
+// new value. See InputInt() for details on the parameters.
+func (wnd *Window) InputFloat64(id string, value *float64, min, max, step float64) (bool, error) {
+	format := func(v float64) string { return fmt.Sprintf(wnd.Style.SliderFloatFormat, v) }
+	newValue, changed, err := wnd.inputNumber(id, *value, min, max, step, false, format)
+	*value = newValue
+	return changed, err
+}
+
+// inputNumber is the implementation of the Input* widgets that works on the
+// value as a float64. It returns the new value and whether or not it changed.
+func (wnd *Window) inputNumber(id string, value, min, max, step float64, isInt bool, format func(float64) string) (float64, bool, error) {
+	// get the font for the text
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return value, false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	originalValue := value
+	clampValue := func(v float64) float64 {
+		if isInt {
+			v = math.Trunc(v)
+		}
+		if min < max {
+			if v < min {
+				v = min
+			} else if v > max {
+				v = max
+			}
+		}
+		return v
+	}
+
+	// the speed the value changes by when dragging or using the arrow keys
+	speed := step
+	if speed <= 0.0 {
+		if isInt {
+			speed = 1.0
+		} else {
+			speed = 0.01
+		}
+	}
+
+	// calculate how much width the step buttons need and leave the rest for the field
+	availW := wnd.takeRowWidth()
+	var buttonsW float32
+	if step > 0.0 {
+		minusW, _, _ := font.GetRenderSize("-")
+		plusW, _, _ := font.GetRenderSize("+")
+		buttonsW = minusW + plusW + 2*(wnd.Style.ButtonPadding[0]+wnd.Style.ButtonPadding[1]+wnd.Style.ButtonMargin[0]+wnd.Style.ButtonMargin[1])
+	}
+	fieldW := availW - buttonsW
+	wnd.requestedItemWidthMaxDC = fieldW
+
+	// if the value is being typed in, show an editbox for the edit buffer
+	// instead of the value.
+	ate := wnd.Owner.getActiveTextEditor()
+	if ate != nil && ate.ID == id {
+		filter := EditboxFilterFloat
+		if isInt {
+			filter = EditboxFilterInt
+		}
+		result, err := wnd.EditboxAdv(id, &ate.EditBuffer, EditboxOptions{Filter: filter})
+		if err != nil {
+			return value, false, err
+		}
+
+		// parse the typed value when Enter is pressed; Escape just drops the
+		// editor without changing the value.
+		if result&EditboxCommitted == EditboxCommitted {
+			parsed, parseErr := strconv.ParseFloat(strings.TrimSpace(ate.EditBuffer), 64)
+			if parseErr == nil {
+				value = clampValue(parsed)
+			}
+		}
+	} else {
+		// start typing in a value when double clicked or activated with the keyboard
+		startEditing := false
+
+		// dragging the mouse over the field changes the value
+		pos := wnd.getCursorDC()
+		_, dimY, _ := font.GetRenderSize("0.0")
+		fieldH := dimY + wnd.Style.SliderPadding[2] + wnd.Style.SliderPadding[3]
+		pressed, _, _ := wnd.sliderHitTest(id)
+		lmbStatus := wnd.Owner.GetMouseButtonAction(0)
+		if pressed && lmbStatus == MouseDown {
+			mouseDeltaX, _ := wnd.Owner.GetMousePositionDelta()
+			value = clampValue(value + float64(mouseDeltaX)*speed)
+		} else if lmbStatus == MouseDoubleClick {
+			mx, my := wnd.getMousePosition()
+			if mx > pos[0] && my > pos[1]-fieldH && mx < pos[0]+fieldW && my < pos[1] {
+				startEditing = true
+			}
+		}
+
+		// the arrow keys nudge the value and Enter starts editing
+		focused, _ := wnd.Owner.registerFocusable(id)
+		if focused {
+			if nudge := wnd.Owner.consumeNudge(); nudge != 0 {
+				value = clampValue(value + float64(nudge)*speed)
+			}
+			if wnd.Owner.consumeActivation() {
+				startEditing = true
+			}
+		}
+
+		if startEditing {
+			text := format(value)
+			textLength := utf8.RuneCountInString(text)
+			if wnd.Owner.setActiveTextEditor(id, textLength) {
+				ate = wnd.Owner.getActiveTextEditor()
+				ate.EditBuffer = text
+				ate.selectRange(0, textLength)
+				wnd.Owner.setFocusFromMouse(id)
+			}
+		}
+
+		if err := wnd.sliderBehavior(id, format(value), 0.0, false); err != nil {
+			return value, false, err
+		}
+	}
+
+	// draw the step buttons
+	if step > 0.0 {
+		minusPressed, err := wnd.Button(id+"#minus", "-")
+		if err != nil {
+			return value, false, err
+		}
+		plusPressed, err := wnd.Button(id+"#plus", "+")
+		if err != nil {
+			return value, false, err
+		}
+		if minusPressed {
+			value = clampValue(value - step)
+		}
+		if plusPressed {
+			value = clampValue(value + step)
+		}
+	}
+
+	return value, value != originalValue, nil
+}
+
+// DragVec2 creates a row of drag slider widgets, one for each component of
+// the vector. Returns true if the value was changed.
+func (wnd *Window) DragVec2(id string, speed float32, value *mgl.Vec2) (bool, error) {
+	return wnd.dragVector(id, speed, value[:])
+}
+
+// DragVec3 creates a row of drag slider widgets, one for each component of
+// the vector. Returns true if the value was changed.
+func (wnd *Window) DragVec3(id string, speed float32, value *mgl.Vec3) (bool, error) {
+	return wnd.dragVector(id, speed, value[:])
+}
+
+// DragVec4 creates a row of drag slider widgets, one for each component of
+// the vector. Returns true if the value was changed.
+func (wnd *Window) DragVec4(id string, speed float32, value *mgl.Vec4) (bool, error) {
+	return wnd.dragVector(id, speed, value[:])
+}
+
+// SliderVec2 creates a row of slider widgets, one for each component of
+// the vector, that use the same min/max values. Returns true if the value was changed.
+func (wnd *Window) SliderVec2(id string, value *mgl.Vec2, min, max float32) (bool, error) {
+	return wnd.sliderVector(id, value[:], min, max)
+}
+
+// SliderVec3 creates a row of slider widgets, one for each component of
+// the vector, that use the same min/max values. Returns true if the value was changed.
+func (wnd *Window) SliderVec3(id string, value *mgl.Vec3, min, max float32) (bool, error) {
+	return wnd.sliderVector(id, value[:], min, max)
+}
+
+// SliderVec4 creates a row of slider widgets, one for each component of
+// the vector, that use the same min/max values. Returns true if the value was changed.
+func (wnd *Window) SliderVec4(id string, value *mgl.Vec4, min, max float32) (bool, error) {
+	return wnd.sliderVector(id, value[:], min, max)
+}
+
+// dragVector is the implementation of the DragVec* widgets.
+func (wnd *Window) dragVector(id string, speed float32, values []float32) (bool, error) {
+	changed := false
+	err := wnd.vectorBehavior(id, "XYZW"[:len(values)], wnd.takeRowWidth(), func(i int, componentID string) error {
+		oldValue := values[i]
+		err := wnd.DragSliderFloat(componentID, speed, &values[i])
+		changed = changed || values[i] != oldValue
+		return err
+	})
+	return changed, err
+}
+
+// sliderVector is the implementation of the SliderVec* widgets.
+func (wnd *Window) sliderVector(id string, values []float32, min, max float32) (bool, error) {
+	changed := false
+	err := wnd.vectorBehavior(id, "XYZW"[:len(values)], wnd.takeRowWidth(), func(i int, componentID string) error {
+		oldValue := values[i]
+		err := wnd.SliderFloat(componentID, &values[i], min, max)
+		changed = changed || values[i] != oldValue
+		return err
+	})
+	return changed, err
+}
+
+// vectorBehavior lays out one field for each rune in labels across widthDC
+// pixels. Each field starts with the rune drawn over the axis color for its
+// position and then editComponent gets called to build the widget for the
+// component with the remaining width requested.
+func (wnd *Window) vectorBehavior(id string, labels string, widthDC float32, editComponent func(i int, componentID string) error) error {
+	axisColors := [4]mgl.Vec4{wnd.Style.AxisXColor, wnd.Style.AxisYColor, wnd.Style.AxisZColor, wnd.Style.AxisWColor}
+
+	componentCount := utf8.RuneCountInString(labels)
+	if componentCount < 1 {
+		return nil
+	}
+	componentW := widthDC / float32(componentCount)
+
+	i := 0
+	for _, r := range labels {
+		labelW, err := wnd.axisLabel(string(r), axisColors[i%len(axisColors)])
+		if err != nil {
+			return err
+		}
+
+		wnd.requestedItemWidthMaxDC = componentW - labelW
+		err = editComponent(i, fmt.Sprintf("%s#%d", id, i))
+		if err != nil {
+			return err
+		}
+		i++
+	}
+
+	return nil
+}
+
+// axisLabel draws the text over a background of the given color with the
+// same height as a slider and returns the width the label took up.
+func (wnd *Window) axisLabel(text string, color mgl.Vec4) (float32, error) {
+	cmd := wnd.getLastCmd()
+
+	// get the font for the text
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return 0.0, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.SliderMargin[0]
+	pos[1] -= wnd.Style.SliderMargin[2]
+
+	// calculate the size necessary for the widget
+	dimX, dimY, _ := font.GetRenderSize(text)
+	labelW := dimX + wnd.Style.SliderPadding[0] + wnd.Style.SliderPadding[1]
+	labelH := dimY + wnd.Style.SliderPadding[2] + wnd.Style.SliderPadding[3]
+
+	// render the label background and text
+	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+labelW, pos[1]-labelH, color, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+	textPos := pos
+	textPos[0] += wnd.Style.SliderPadding[0]
+	textPos[1] -= wnd.Style.SliderPadding[2]
+	renderData := font.CreateText(textPos, wnd.Style.SliderTextColor, text)
+	cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+
+	// advance the cursor; the field that follows adds its own margin
+	wnd.addCursorHorizontalDelta(labelW + wnd.Style.SliderMargin[0])
+	wnd.setNextRowCursorOffset(labelH + wnd.Style.SliderMargin[2] + wnd.Style.SliderMargin[3])
+
+	return labelW + wnd.Style.SliderMargin[0], nil
+}
+
+// the modes of the ColorEdit4 widget
+const (
+	colorEditRGB = 0
+	colorEditHSV = 1
+)
+
+// colorEditSpeed is how much a color component changes for each pixel the
+// mouse is dragged in the ColorEdit4 widget.
+const colorEditSpeed = 1.0 / 255.0
+
+// ColorEdit4 creates a row of drag slider widgets to edit the color components,
+// a button to toggle editing the color as RGBA or HSVA and a swatch showing the
+// color that opens a color picker when pressed; see ColorPicker4().
+// Returns true if the color was changed.
+func (wnd *Window) ColorEdit4(id string, color *mgl.Vec4) (bool, error) {
+	// get the font for the text
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	widthDC := wnd.takeRowWidth()
+
+	// the mode button is sized for the widest label so that the fields don't
+	// move when it's toggled
+	modeID := id + "#mode"
+	mode, _ := wnd.getStoredInt(modeID)
+	rgbW, _, _ := font.GetRenderSize("RGBA")
+	hsvW, _, _ := font.GetRenderSize("HSVA")
+	if hsvW > rgbW {
+		rgbW = hsvW
+	}
+	modeW := rgbW + wnd.Style.ButtonPadding[0] + wnd.Style.ButtonPadding[1] + wnd.Style.ButtonMargin[0] + wnd.Style.ButtonMargin[1]
+
+	// the swatch is a square as high as a slider
+	swatchH, err := wnd.swatchSize()
+	if err != nil {
+		return false, err
+	}
+	swatchW := swatchH + wnd.Style.SliderMargin[0] + wnd.Style.SliderMargin[1]
+
+	// draw the button to toggle the mode
+	labels := "RGBA"
+	if mode == colorEditHSV {
+		labels = "HSVA"
+	}
+	wnd.requestedItemWidthMinDC = modeW
+	pressed, err := wnd.Button(modeID, labels)
+	if err != nil {
+		return false, err
+	}
+	if pressed {
+		if mode == colorEditHSV {
+			mode = colorEditRGB
+		} else {
+			mode = colorEditHSV
+		}
+		wnd.setStoredInt(modeID, mode)
+	}
+
+	// edit the components for the mode the color was drawn in
+	components := *color
+	if labels == "HSVA" {
+		components[0], components[1], components[2] = RGBToHSV(color[0], color[1], color[2])
+	}
+	changed := false
+	err = wnd.vectorBehavior(id, labels, widthDC-modeW-swatchW, func(i int, componentID string) error {
+		oldValue := components[i]
+		err := wnd.DragSliderFloat(componentID, colorEditSpeed, &components[i])
+		components[i] = ClipF32(0.0, 1.0, components[i])
+		changed = changed || components[i] != oldValue
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		if labels == "HSVA" {
+			components[0], components[1], components[2] = HSVToRGB(components[0], components[1], components[2])
+		}
+		*color = components
+	}
+
+	// the swatch opens the color picker
+	pickerChanged, err := wnd.ColorPicker4(id+"#picker", color)
+	return changed || pickerChanged, err
 }
 
 // sliderHitTest calculates the size of the widget and then
@@ -892,10 +2093,11 @@ func (wnd *Window) sliderHitTest(id string) (bool, float32, float32) {
 		}
 
 		// try to claim focus -- wont work if something already claimed it this mouse press
-		mx, my := wnd.Owner.GetMouseDownPosition(0)
+		mx, my := wnd.getMouseDownPosition(0)
 		if mx > pos[0] && my > pos[1]-sliderH && mx < pos[0]+sliderW && my < pos[1] {
 			claimed := wnd.Owner.SetActiveInputID(id)
 			if claimed {
+				wnd.Owner.setFocusFromMouse(id)
 				return true, sliderW, sliderH
 			}
 		}
@@ -905,7 +2107,7 @@ func (wnd *Window) sliderHitTest(id string) (bool, float32, float32) {
 }
 
 // sliderBehavior is the actual action of drawing the slider widget.
-func (wnd *Window) sliderBehavior(valueString string, valueRatio float32, drawCursor bool) error {
+func (wnd *Window) sliderBehavior(id string, valueString string, valueRatio float32, drawCursor bool) error {
 	cmd := wnd.getLastCmd()
 
 	// get the font for the text
@@ -935,6 +2137,7 @@ func (wnd *Window) sliderBehavior(valueString string, valueRatio float32, drawCu
 	// render the widget background
 	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+sliderW, pos[1]-sliderH, bgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
 	cmd.AddFaces(combos, indexes, fc)
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], sliderW, sliderH)
 
 	if drawCursor {
 		// calculate how much of the slider control is available to the cursor for
@@ -1051,15 +2254,27 @@ func (wnd *Window) Custom(widthS, heightS float32, margin mgl.Vec4, customDraw f
 }
 
 // Editbox creates an editbox control that changes the value string.
+// Returns true if the value was changed or Enter was pressed this frame.
 func (wnd *Window) Editbox(id string, value *string) (bool, error) {
+	result, err := wnd.EditboxAdv(id, value, EditboxOptions{})
+	return result != 0, err
+}
+
+// EditboxAdv creates an editbox control that changes the value string with
+// the input restrictions and display settings in options. Returns a
+// combination of the EditboxChanged and EditboxCommitted flags.
+func (wnd *Window) EditboxAdv(id string, value *string, options EditboxOptions) (int, error) {
 	cmd := wnd.getLastCmd()
 
 	// get the font for the text
 	font := wnd.Owner.GetFont(wnd.Style.FontName)
 	if font == nil {
-		return false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+		return 0, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
 	}
 
+	// keep the editing state and undo history alive while the widget is built
+	wnd.Owner.keepTextEditState(id)
+
 	// calculate the location for the widget
 	pos := wnd.getCursorDC()
 	pos[0] += wnd.Style.EditboxMargin[0]
@@ -1069,6 +2284,9 @@ func (wnd *Window) Editbox(id string, value *string) (bool, error) {
 	// a const string to calculate the height.
 	_, _, wndWidth, _ := wnd.GetDisplaySize()
 	textToSize := *value
+	if len(textToSize) == 0 {
+		textToSize = options.Placeholder
+	}
 	if len(textToSize) == 0 {
 		textToSize = "FIXEDSIZE"
 	}
@@ -1083,30 +2301,89 @@ func (wnd *Window) Editbox(id string, value *string) (bool, error) {
 	// set a default color for the button
 	bgColor := wnd.Style.EditboxBgColor
 
+	// displayText returns the value as it should be drawn which is masked
+	// for passwords; it has the same number of runes as the value.
+	displayText := func() string {
+		if options.Password {
+			return strings.Repeat("*", utf8.RuneCountInString(*value))
+		}
+		return *value
+	}
+
+	// textIndexAtMouse returns the rune index in the value closest to the mouse x position
+	textIndexAtMouse := func(mx float32, charShift int) int {
+		return font.IndexForOffsetAdv(displayText(), charShift, mx-pos[0]-wnd.Style.EditboxPadding[0])
+	}
+
 	// test to see if the mouse is inside the widget
 	lmbStatus := wnd.Owner.GetMouseButtonAction(0)
-	if lmbStatus != MouseUp {
+	if lmbStatus == MouseDown {
 		// are  we already the active widget?
 		if wnd.Owner.GetActiveInputID() != id {
 			// try to claim focus -- wont work if something already claimed it this mouse press
-			mx, my := wnd.Owner.GetMouseDownPosition(0)
+			mx, my := wnd.getMouseDownPosition(0)
 			if mx > pos[0] && my > pos[1]-editboxH && mx < pos[0]+editboxW && my < pos[1] {
-				wnd.Owner.SetActiveInputID(id)
-				wnd.Owner.setActiveTextEditor(id, 0)
+				if wnd.Owner.SetActiveInputID(id) {
+					// place the cursor where the mouse was pressed
+					if !wnd.Owner.setActiveTextEditor(id, textIndexAtMouse(mx, 0)) {
+						ate := wnd.Owner.getActiveTextEditor()
+						if ate != nil && ate.ID == id {
+							ate.moveCursor(textIndexAtMouse(mx, ate.CharacterShift), false)
+						}
+					}
+					wnd.Owner.setFocusFromMouse(id)
+				}
+			}
+		} else {
+			// the mouse is being dragged after pressing in the editbox, so
+			// extend the selection to the mouse position
+			ate := wnd.Owner.getActiveTextEditor()
+			if ate != nil && ate.ID == id {
+				mx, _ := wnd.getMousePosition()
+				ate.moveCursor(textIndexAtMouse(mx, ate.CharacterShift), true)
 			}
 		}
+	} else if lmbStatus == MouseDoubleClick {
+		// select the word under the mouse
+		ate := wnd.Owner.getActiveTextEditor()
+		mx, my := wnd.getMousePosition()
+		if ate != nil && ate.ID == id && mx > pos[0] && my > pos[1]-editboxH && mx < pos[0]+editboxW && my < pos[1] {
+			start, end := wordRangeAt([]rune(displayText()), textIndexAtMouse(mx, ate.CharacterShift))
+			ate.selectRange(start, end)
+		}
+	}
+
+	// keyboard navigation moving the focus here or activating the editbox
+	// with the keyboard starts editing with the cursor at the end.
+	focused, arrived := wnd.Owner.registerFocusable(id)
+	if focused && wnd.Owner.getActiveTextEditor() == nil {
+		if arrived || wnd.Owner.consumeActivation() {
+			wnd.Owner.setActiveTextEditor(id, utf8.RuneCountInString(*value))
+		}
 	}
 
 	// see if we're the active editor. if so, then we can consume the key events;
 	// otherwise we leave them be.
+	result := 0
 	editorState := wnd.Owner.getActiveTextEditor()
 	if editorState != nil && editorState.ID == id {
 		// we're the active editor so set the background color accordingly
 		bgColor = wnd.Style.EditboxActiveColor
+		editorState.Options = options
+
+		// work on the runes of the string so that multi-byte characters
+		// are edited as a whole; the cursor offset counts runes.
+		valueRunes := []rune(*value)
+		editorState.clampToLength(len(valueRunes))
 
 		// grab the key events
 		keyEvents := wnd.Owner.GetKeyEvents()
 		for _, event := range keyEvents {
+			// stop processing keys if the editor gave up the focus
+			if editorState == nil {
+				break
+			}
+
 			if event.IsRune == false {
 				// all of these keys reset the timer if it doesn't lose focus, so
 				// just reset it here for convenience
@@ -1114,72 +2391,74 @@ func (wnd *Window) Editbox(id string, value *string) (bool, error) {
 
 				// handle the key events specially in their own way
 				switch event.KeyCode {
-				case EweyKeyRight:
-					if editorState.CursorOffset < len(*value) {
-						editorState.CursorOffset++
-					}
-				case EweyKeyLeft:
-					if editorState.CursorOffset > 0 {
-						editorState.CursorOffset--
-					}
-					if editorState.CharacterShift > 0 {
-						editorState.CharacterShift--
-					}
-				case EweyKeyBackspace:
-					// erase the rune previous to the cursor
-					if editorState.CursorOffset > 0 {
-						newString := (*value)[:editorState.CursorOffset-1] + (*value)[editorState.CursorOffset:]
-						*value = newString
-						editorState.CursorOffset--
-					}
-					if editorState.CharacterShift > 0 {
-						editorState.CharacterShift--
-					}
-				case EweyKeyDelete:
-					// erase the rune just after the cursor
-					if editorState.CursorOffset < len(*value) {
-						newString := (*value)[:editorState.CursorOffset] + (*value)[editorState.CursorOffset+1:]
-						*value = newString
-					}
-				case EweyKeyEnter, EweyKeyEscape:
+				case EweyKeyEnter:
+					// commit the value and give up the focus voluntarily here
+					result |= EditboxCommitted
+					wnd.Owner.releaseTextEditor(0)
+					editorState = nil
+				case EweyKeyEscape:
 					// give up the focus voluntarily here
-					wnd.Owner.clearActiveTextEditor()
-					wnd.Owner.ClearActiveInputID()
+					wnd.Owner.releaseTextEditor(0)
+					editorState = nil
+				case EweyKeyTab:
+					// give up the focus and move it to the next widget
+					if event.ShiftDown {
+						wnd.Owner.releaseTextEditor(-1)
+					} else {
+						wnd.Owner.releaseTextEditor(1)
+					}
 					editorState = nil
 				case EweyKeyEnd:
-					editorState.CursorOffset = len(*value)
+					editorState.moveCursor(len(valueRunes), event.ShiftDown)
 				case EweyKeyHome:
-					editorState.CursorOffset = 0
+					editorState.moveCursor(0, event.ShiftDown)
 					editorState.CharacterShift = 0
-				case EweyKeyInsert:
-					if event.ShiftDown {
-						clippy, _ := wnd.Owner.GetClipboardString()
-						newString := (*value)[:editorState.CursorOffset] + clippy + (*value)[editorState.CursorOffset:]
-						*value = newString
-					}
+				default:
+					valueRunes, _ = editorState.applyEditKey(wnd.Owner, valueRunes, event)
 				}
 			} else {
-				// do some special testing for clipboard commands
-				if event.Rune == 'V' && event.CtrlDown {
-					clippy, _ := wnd.Owner.GetClipboardString()
-					newString := (*value)[:editorState.CursorOffset] + clippy + (*value)[editorState.CursorOffset:]
-					*value = newString
-				} else {
-					// insert the rune into the value string
-					newString := (*value)[:editorState.CursorOffset] + string(event.Rune) + (*value)[editorState.CursorOffset:]
-					*value = newString
-					editorState.CursorOffset++
-				}
+				valueRunes, _ = editorState.applyEditKey(wnd.Owner, valueRunes, event)
 			}
 		}
 
+		// make sure the cursor is visible if it moved before the shifted text
+		if editorState != nil && editorState.CharacterShift > editorState.CursorOffset {
+			editorState.CharacterShift = editorState.CursorOffset
+		}
+
+		newValue := string(valueRunes)
+		if newValue != *value {
+			*value = newValue
+			result |= EditboxChanged
+		}
 	}
 
 	// render the button background
 	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+editboxW, pos[1]-editboxH, bgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
 	cmd.AddFaces(combos, indexes, fc)
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], editboxW, editboxH)
+
+	// render the selection highlight behind the text
+	if editorState != nil && editorState.ID == id && editorState.hasSelection() {
+		selStart, selEnd := editorState.selectionRange()
+		if selStart < editorState.CharacterShift {
+			selStart = editorState.CharacterShift
+		}
+		maxX := editboxW - wnd.Style.EditboxPadding[1]
+		selX1 := wnd.Style.EditboxPadding[0] + font.OffsetForIndexAdv(displayText(), editorState.CharacterShift, selStart)
+		selX2 := wnd.Style.EditboxPadding[0] + font.OffsetForIndexAdv(displayText(), editorState.CharacterShift, selEnd)
+		if selX2 > maxX {
+			selX2 = maxX
+		}
+		if selX1 < selX2 {
+			combos, indexes, fc = cmd.DrawRectFilledDC(pos[0]+selX1, pos[1]-wnd.Style.EditboxPadding[2], pos[0]+selX2, pos[1]-editboxH+wnd.Style.EditboxPadding[3],
+				wnd.Style.EditboxSelectedColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			cmd.AddFaces(combos, indexes, fc)
+		}
+	}
 
-	// create the text for the button if the string is not empty
+	// create the text for the button if the string is not empty; otherwise
+	// draw the placeholder text if there is any.
 	if len(*value) > 0 {
 		textPos := pos
 		textPos[0] += wnd.Style.EditboxPadding[0]
@@ -1190,7 +2469,7 @@ func (wnd *Window) Editbox(id string, value *string) (bool, error) {
 			cursorPos = editorState.CursorOffset
 			textOffset = editorState.CharacterShift
 		}
-		renderData := font.CreateTextAdv(textPos, wnd.Style.EditboxTextColor, editboxW-wnd.Style.EditboxCursorWidth, textOffset, cursorPos, *value)
+		renderData := font.CreateTextAdv(textPos, wnd.Style.EditboxTextColor, editboxW-wnd.Style.EditboxCursorWidth, textOffset, cursorPos, displayText())
 		cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
 
 		// if we overflowed the cursor, start shifting the text over one frame at a time until
@@ -1198,6 +2477,12 @@ func (wnd *Window) Editbox(id string, value *string) (bool, error) {
 		if renderData.CursorOverflowRight {
 			editorState.CharacterShift++
 		}
+	} else if len(options.Placeholder) > 0 {
+		textPos := pos
+		textPos[0] += wnd.Style.EditboxPadding[0]
+		textPos[1] -= wnd.Style.EditboxPadding[2]
+		renderData := font.CreateTextAdv(textPos, wnd.Style.EditboxHintColor, editboxW-wnd.Style.EditboxCursorWidth, -1, -1, options.Placeholder)
+		cmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
 	}
 
 	// if we're the active editor, deal with drawing the cursor here
@@ -1212,7 +2497,7 @@ func (wnd *Window) Editbox(id string, value *string) (bool, error) {
 
 		// draw the cursor if we're within the blink duration
 		if editorState.CursorTimer < wnd.Style.EditboxBlinkDuration {
-			cursorOffsetDC := font.OffsetForIndexAdv(*value, editorState.CharacterShift, editorState.CursorOffset)
+			cursorOffsetDC := font.OffsetForIndexAdv(displayText(), editorState.CharacterShift, editorState.CursorOffset)
 			cursorOffsetDC += wnd.Style.EditboxPadding[0]
 
 			// render the editbox cursor
@@ -1226,7 +2511,350 @@ func (wnd *Window) Editbox(id string, value *string) (bool, error) {
 	wnd.addCursorHorizontalDelta(editboxW + wnd.Style.EditboxMargin[0] + wnd.Style.EditboxMargin[1])
 	wnd.setNextRowCursorOffset(editboxH + wnd.Style.EditboxMargin[2] + wnd.Style.EditboxMargin[3])
 
-	return true, nil
+	return result, nil
+}
+
+// EditboxMultiline creates a text editor control for multiple lines of text
+// that changes the value string. The heightS parameter is the height of the
+// widget in screen space; the width fills the rest of the row. Text that
+// doesn't fit in the widget can be scrolled with the mouse wheel or by
+// moving the cursor. Returns true if the value was changed this frame.
+func (wnd *Window) EditboxMultiline(id string, value *string, heightS float32) (bool, error) {
+	// get the font for the text
+	font := wnd.Owner.GetFont(wnd.Style.FontName)
+	if font == nil {
+		return false, fmt.Errorf("Couldn't access font %s from the Manager.", wnd.Style.FontName)
+	}
+
+	// keep the editing state and undo history alive while the widget is built
+	wnd.Owner.keepTextEditState(id)
+
+	// calculate the location for the widget
+	pos := wnd.getCursorDC()
+	pos[0] += wnd.Style.EditboxMargin[0]
+	pos[1] -= wnd.Style.EditboxMargin[2]
+
+	// calculate the size necessary for the widget
+	_, _, wndWidth, _ := wnd.GetDisplaySize()
+	_, editboxH := wnd.Owner.ScreenToDisplay(0.0, heightS)
+	editboxW := wndWidth - wnd.widgetCursorDC[0] - wnd.Style.WindowPadding[1] - wnd.Style.EditboxMargin[1]
+
+	// clamp the width to the requsted size
+	editboxW = wnd.clampWidgetWidthToReqW(editboxW)
+	editboxW = editboxW - wnd.Style.EditboxMargin[0] - wnd.Style.EditboxMargin[1]
+
+	// calculate the area available for the text
+	lineH := font.GlyphHeight * font.GetCurrentScale()
+	textX := pos[0] + wnd.Style.EditboxPadding[0]
+	textY := pos[1] - wnd.Style.EditboxPadding[2]
+	textW := editboxW - wnd.Style.EditboxPadding[0] - wnd.Style.EditboxPadding[1]
+	textH := editboxH - wnd.Style.EditboxPadding[2] - wnd.Style.EditboxPadding[3]
+	visibleLines := int(textH / lineH)
+	if visibleLines < 1 {
+		visibleLines = 1
+	}
+
+	// the scroll position is kept in the window storage so that it persists
+	// when the editor isn't active.
+	scrollXKey := id + "#scrollX"
+	scrollYKey := id + "#scrollY"
+	storedScrollX, _ := wnd.getStoredInt(scrollXKey)
+	storedScrollY, _ := wnd.getStoredInt(scrollYKey)
+	scrollX := float32(storedScrollX)
+	scrollY := float32(storedScrollY)
+
+	valueRunes := []rune(*value)
+	starts := lineStarts(valueRunes)
+	changed := false
+
+	// textIndexAtMouse returns the rune index in the value closest to the mouse position
+	textIndexAtMouse := func(mx, my float32) int {
+		line := int((textY - my + scrollY) / lineH)
+		if line < 0 {
+			return 0
+		}
+		if line >= len(starts) {
+			return len(valueRunes)
+		}
+		lineText := string(valueRunes[starts[line]:lineEnd(valueRunes, starts, line)])
+		return starts[line] + font.IndexForOffset(lineText, mx-textX+scrollX)
+	}
+
+	// set a default color for the background
+	bgColor := wnd.Style.EditboxBgColor
+
+	// test to see if the mouse is inside the widget
+	mouseX, mouseY := wnd.getMousePosition()
+	mouseInside := mouseX > pos[0] && mouseY > pos[1]-editboxH && mouseX < pos[0]+editboxW && mouseY < pos[1]
+	lmbStatus := wnd.Owner.GetMouseButtonAction(0)
+	if lmbStatus == MouseDown {
+		// are  we already the active widget?
+		if wnd.Owner.GetActiveInputID() != id {
+			// try to claim focus -- wont work if something already claimed it this mouse press
+			mx, my := wnd.getMouseDownPosition(0)
+			if mx > pos[0] && my > pos[1]-editboxH && mx < pos[0]+editboxW && my < pos[1] {
+				if wnd.Owner.SetActiveInputID(id) {
+					// place the cursor where the mouse was pressed
+					clickIndex := textIndexAtMouse(mx, my)
+					if !wnd.Owner.setActiveTextEditor(id, clickIndex) {
+						ate := wnd.Owner.getActiveTextEditor()
+						if ate != nil && ate.ID == id {
+							ate.moveCursor(clickIndex, false)
+						}
+					}
+					wnd.Owner.setFocusFromMouse(id)
+				}
+			}
+		} else {
+			// the mouse is being dragged after pressing in the editor, so
+			// extend the selection to the mouse position
+			ate := wnd.Owner.getActiveTextEditor()
+			if ate != nil && ate.ID == id {
+				ate.moveCursor(textIndexAtMouse(mouseX, mouseY), true)
+			}
+		}
+	} else if lmbStatus == MouseDoubleClick && mouseInside {
+		// select the word under the mouse
+		ate := wnd.Owner.getActiveTextEditor()
+		if ate != nil && ate.ID == id {
+			start, end := wordRangeAt(valueRunes, textIndexAtMouse(mouseX, mouseY))
+			ate.selectRange(start, end)
+		}
+	}
+
+	// keyboard navigation moving the focus here or activating the editor
+	// with the keyboard starts editing with the cursor at the end.
+	focused, arrived := wnd.Owner.registerFocusable(id)
+	if focused && wnd.Owner.getActiveTextEditor() == nil {
+		if arrived || wnd.Owner.consumeActivation() {
+			wnd.Owner.setActiveTextEditor(id, len(valueRunes))
+		}
+	}
+
+	// moveLines moves the cursor up or down a number of lines, keeping it as
+	// close as possible to the same horizontal position.
+	moveLines := func(ate *textEditState, delta int, extendSelection bool) {
+		line := lineForOffset(starts, ate.CursorOffset)
+		target := line + delta
+		if target < 0 {
+			ate.moveCursor(0, extendSelection)
+			return
+		}
+		if target >= len(starts) {
+			ate.moveCursor(len(valueRunes), extendSelection)
+			return
+		}
+		lineText := string(valueRunes[starts[line]:lineEnd(valueRunes, starts, line)])
+		cursorX := font.OffsetForIndex(lineText, ate.CursorOffset-starts[line])
+		targetText := string(valueRunes[starts[target]:lineEnd(valueRunes, starts, target)])
+		ate.moveCursor(starts[target]+font.IndexForOffset(targetText, cursorX), extendSelection)
+	}
+
+	// see if we're the active editor. if so, then we can consume the key events;
+	// otherwise we leave them be.
+	editorState := wnd.Owner.getActiveTextEditor()
+	if editorState != nil && editorState.ID == id {
+		// we're the active editor so set the background color accordingly
+		bgColor = wnd.Style.EditboxActiveColor
+		editorState.clampToLength(len(valueRunes))
+		cursorMoved := false
+
+		// grab the key events
+		keyEvents := wnd.Owner.GetKeyEvents()
+		for _, event := range keyEvents {
+			// stop processing keys if the editor gave up the focus
+			if editorState == nil {
+				break
+			}
+
+			cursorMoved = true
+			if event.IsRune == false {
+				// all of these keys reset the timer if it doesn't lose focus, so
+				// just reset it here for convenience
+				editorState.CursorTimer = 0.0
+
+				// handle the key events specially in their own way
+				switch event.KeyCode {
+				case EweyKeyEnter:
+					newline := KeyPressEvent{Rune: '\n', IsRune: true}
+					valueRunes, _ = editorState.applyEditKey(wnd.Owner, valueRunes, newline)
+				case EweyKeyEscape:
+					// give up the focus voluntarily here
+					wnd.Owner.releaseTextEditor(0)
+					editorState = nil
+				case EweyKeyTab:
+					// give up the focus and move it to the next widget
+					if event.ShiftDown {
+						wnd.Owner.releaseTextEditor(-1)
+					} else {
+						wnd.Owner.releaseTextEditor(1)
+					}
+					editorState = nil
+				case EweyKeyUp:
+					moveLines(editorState, -1, event.ShiftDown)
+				case EweyKeyDown:
+					moveLines(editorState, 1, event.ShiftDown)
+				case EweyKeyPageUp:
+					moveLines(editorState, -visibleLines, event.ShiftDown)
+				case EweyKeyPageDown:
+					moveLines(editorState, visibleLines, event.ShiftDown)
+				case EweyKeyHome:
+					if event.CtrlDown {
+						editorState.moveCursor(0, event.ShiftDown)
+					} else {
+						line := lineForOffset(starts, editorState.CursorOffset)
+						editorState.moveCursor(starts[line], event.ShiftDown)
+					}
+				case EweyKeyEnd:
+					if event.CtrlDown {
+						editorState.moveCursor(len(valueRunes), event.ShiftDown)
+					} else {
+						line := lineForOffset(starts, editorState.CursorOffset)
+						editorState.moveCursor(lineEnd(valueRunes, starts, line), event.ShiftDown)
+					}
+				default:
+					valueRunes, _ = editorState.applyEditKey(wnd.Owner, valueRunes, event)
+				}
+			} else {
+				valueRunes, _ = editorState.applyEditKey(wnd.Owner, valueRunes, event)
+			}
+
+			// the lines need to be found again after every edit
+			starts = lineStarts(valueRunes)
+		}
+
+		newValue := string(valueRunes)
+		if newValue != *value {
+			*value = newValue
+			changed = true
+		}
+
+		// scroll the text to keep the cursor visible after keyboard input or
+		// while the mouse is selecting text.
+		if editorState != nil && (cursorMoved || wnd.Owner.GetActiveInputID() == id) {
+			line := lineForOffset(starts, editorState.CursorOffset)
+			lineText := string(valueRunes[starts[line]:lineEnd(valueRunes, starts, line)])
+			cursorX := font.OffsetForIndex(lineText, editorState.CursorOffset-starts[line])
+			cursorTop := float32(line) * lineH
+			if cursorTop < scrollY {
+				scrollY = cursorTop
+			} else if cursorTop+lineH > scrollY+textH {
+				scrollY = cursorTop + lineH - textH
+			}
+			if cursorX < scrollX {
+				scrollX = cursorX
+			} else if cursorX+wnd.Style.EditboxCursorWidth > scrollX+textW {
+				scrollX = cursorX + wnd.Style.EditboxCursorWidth - textW
+			}
+		}
+	}
+
+	// scroll the text with the mouse wheel
+	if mouseInside {
+		wheelDelta := wnd.Owner.GetScrollWheelDelta(true)
+		if wheelDelta != 0.0 {
+			scrollY -= wheelDelta
+		}
+		wnd.Owner.wheelCaptured = true
+	}
+
+	// keep the scroll position within the text
+	maxScrollY := float32(len(starts))*lineH - textH
+	if scrollY > maxScrollY {
+		scrollY = maxScrollY
+	}
+	if scrollY < 0.0 {
+		scrollY = 0.0
+	}
+	if scrollX < 0.0 {
+		scrollX = 0.0
+	}
+	wnd.setStoredInt(scrollXKey, int(scrollX))
+	wnd.setStoredInt(scrollYKey, int(scrollY))
+	scrollX = float32(int(scrollX))
+	scrollY = float32(int(scrollY))
+
+	// render the background
+	cmd := wnd.getLastCmd()
+	combos, indexes, fc := cmd.DrawRectFilledDC(pos[0], pos[1], pos[0]+editboxW, pos[1]-editboxH, bgColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+	cmd.AddFaces(combos, indexes, fc)
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], editboxW, editboxH)
+
+	// the text gets clipped to the inside of the widget
+	textCmd := wnd.addClippedCmd(textX, textY, textW, textH)
+	isEditing := editorState != nil && editorState.ID == id
+	firstLine := int(scrollY / lineH)
+	for line := firstLine; line < len(starts) && line <= firstLine+visibleLines+1; line++ {
+		lineStart := starts[line]
+		lineStop := lineEnd(valueRunes, starts, line)
+		lineText := string(valueRunes[lineStart:lineStop])
+		lineX := textX - scrollX
+		lineY := textY - float32(line)*lineH + scrollY
+
+		// render the selection highlight behind the text
+		if isEditing && editorState.hasSelection() {
+			selStart, selEnd := editorState.selectionRange()
+			if selStart < lineStart {
+				selStart = lineStart
+			}
+			if selEnd > lineStop {
+				selEnd = lineStop
+			}
+			if selStart <= selEnd && selStart <= lineStop && selEnd >= lineStart {
+				selX1 := font.OffsetForIndex(lineText, selStart-lineStart)
+				selX2 := font.OffsetForIndex(lineText, selEnd-lineStart)
+
+				// show that the newline is selected too
+				_, fullSelEnd := editorState.selectionRange()
+				if fullSelEnd > lineStop {
+					selX2 += wnd.Style.EditboxCursorWidth
+				}
+				if selX1 < selX2 {
+					combos, indexes, fc = textCmd.DrawRectFilledDC(lineX+selX1, lineY, lineX+selX2, lineY-lineH,
+						wnd.Style.EditboxSelectedColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+					textCmd.AddFaces(combos, indexes, fc)
+				}
+			}
+		}
+
+		if len(lineText) > 0 {
+			renderData := font.CreateTextAdv(mgl.Vec3{lineX, lineY, 0}, wnd.Style.EditboxTextColor, scrollX+textW, 0, -1, lineText)
+			textCmd.AddFaces(renderData.ComboBuffer, renderData.IndexBuffer, renderData.Faces)
+		}
+	}
+
+	// if we're the active editor, deal with drawing the cursor here
+	if isEditing {
+		// add the current delta to the timer
+		editorState.CursorTimer += float32(wnd.Owner.FrameDelta)
+
+		// did we overflow the blink interval? if so, reset the timer
+		if editorState.CursorTimer > wnd.Style.EditboxBlinkInterval {
+			editorState.CursorTimer -= wnd.Style.EditboxBlinkInterval
+		}
+
+		// draw the cursor if we're within the blink duration
+		if editorState.CursorTimer < wnd.Style.EditboxBlinkDuration {
+			line := lineForOffset(starts, editorState.CursorOffset)
+			lineText := string(valueRunes[starts[line]:lineEnd(valueRunes, starts, line)])
+			cursorX := textX - scrollX + font.OffsetForIndex(lineText, editorState.CursorOffset-starts[line])
+			cursorY := textY - float32(line)*lineH + scrollY
+
+			// render the editbox cursor
+			combos, indexes, fc = textCmd.DrawRectFilledDC(cursorX, cursorY, cursorX+wnd.Style.EditboxCursorWidth, cursorY-lineH,
+				wnd.Style.EditboxCursorColor, defaultTextureSampler, wnd.Owner.whitePixelUv)
+			textCmd.AddFaces(combos, indexes, fc)
+		}
+	}
+
+	// start a new command so that other widgets aren't clipped to the text area
+	wnd.addNewCmd()
+
+	// advance the cursor for the width of the text widget
+	wnd.addCursorHorizontalDelta(editboxW + wnd.Style.EditboxMargin[0] + wnd.Style.EditboxMargin[1])
+	wnd.setNextRowCursorOffset(editboxH + wnd.Style.EditboxMargin[2] + wnd.Style.EditboxMargin[3])
+
+	return changed, nil
 }
 
 // TreeNode draws the tree node widget on screen with the given text. Returns a
@@ -1262,6 +2890,9 @@ func (wnd *Window) TreeNode(id string, text string) (bool, error) {
 		openState = true
 	}
 
+	// keyboard activation toggles the node just like a click
+	_, activated := wnd.focusBehavior(id)
+
 	// test to see if the mouse is inside the widget
 	pressed := false
 	buttonTest := wnd.buttonBehavior(id, pos[0], pos[1], nodeW, nodeH)
@@ -1270,7 +2901,7 @@ func (wnd *Window) TreeNode(id string, text string) (bool, error) {
 	}
 
 	// if it's pressed, we invert the state and store the updated value
-	if pressed {
+	if pressed || activated {
 		openState = !openState
 		if openState == false {
 			wnd.setStoredInt(id, 0)
@@ -1279,6 +2910,8 @@ func (wnd *Window) TreeNode(id string, text string) (bool, error) {
 		}
 	}
 
+	wnd.drawFocusHighlight(cmd, id, pos[0], pos[1], nodeW+nodeH*0.25+4, nodeH)
+
 	// render the node icons in a square
 	iconX1 := pos[0]
 	iconY1 := pos[1] - nodeH*0.375
@@ -1326,7 +2959,7 @@ func (wnd *Window) buttonBehavior(id string, minX, minY, width, height float32)
 	result := buttonNoAction
 
 	// test to see if the mouse is inside the widget
-	mx, my := wnd.Owner.GetMousePosition()
+	mx, my := wnd.getMousePosition()
 	if mx > minX && my > minY-height && mx < minX+width && my < minY {
 		lmbStatus := wnd.Owner.GetMouseButtonAction(0)
 
@@ -1336,10 +2969,12 @@ func (wnd *Window) buttonBehavior(id string, minX, minY, width, height float32)
 			result = buttonHover
 		} else {
 			// mouse is down, but was it pressed inside the button?
-			mdx, mdy := wnd.Owner.GetMouseDownPosition(0)
+			mdx, mdy := wnd.getMouseDownPosition(0)
 			if mdx > minX && mdy > minY-height && mdx < minX+width && mdy < minY {
 				result = buttonHover
-				wnd.Owner.SetActiveInputID(id)
+				if wnd.Owner.SetActiveInputID(id) {
+					wnd.Owner.setFocusFromMouse(id)
+				}
 			}
 		}
 	}